@@ -0,0 +1,253 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"seattle-commute-cli/transit"
+)
+
+// Alert is a service alert and what it applies to. Lines are matched with
+// transit.Step.OnLine ("545", "1 Line"); stops by name.
+type Alert struct {
+	ID          string
+	Effect      string
+	Header      string
+	Description string
+	URL         string
+	Lines       []string
+	Stops       []string
+	Periods     []Period
+}
+
+// Period is when an alert is in force; a zero Start or End is open-ended.
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// ActiveAt reports whether the alert is in force at t. Alerts without
+// periods always are.
+func (a Alert) ActiveAt(t time.Time) bool {
+	if len(a.Periods) == 0 {
+		return true
+	}
+	for _, p := range a.Periods {
+		if (p.Start.IsZero() || !t.Before(p.Start)) && (p.End.IsZero() || !t.After(p.End)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Affects reports whether the alert concerns a ride: its line, or the stop
+// it boards or leaves at.
+func (a Alert) Affects(step transit.Step) bool {
+	for _, line := range a.Lines {
+		if step.OnLine(line) {
+			return true
+		}
+	}
+	for _, stop := range a.Stops {
+		if strings.EqualFold(stop, step.DepartureStop) || strings.EqualFold(stop, step.ArrivalStop) {
+			return true
+		}
+	}
+	return false
+}
+
+// Attach sets Alerts on each route to the alerts affecting one of its rides
+// while it's riding.
+func Attach(routes []transit.Route, alerts []Alert) {
+	for i := range routes {
+		routes[i].Alerts = nil
+		for _, a := range alerts {
+			for _, step := range routes[i].TransitSteps() {
+				if a.Affects(step) && a.ActiveAt(step.DepartTime) {
+					routes[i].Alerts = append(routes[i].Alerts, transit.Alert{
+						Effect:      a.Effect,
+						Header:      a.Header,
+						Description: a.Description,
+						URL:         a.URL,
+					})
+					break
+				}
+			}
+		}
+	}
+}
+
+// Load reads alerts from a feed URL or a local file, e.g. a fixture.
+func Load(ctx context.Context, feed string) ([]Alert, error) {
+	var data []byte
+	var err error
+	if strings.HasPrefix(feed, "http://") || strings.HasPrefix(feed, "https://") {
+		data, err = fetch(ctx, feed)
+	} else {
+		data, err = os.ReadFile(feed)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alerts from %s: %v", feed, err)
+	}
+
+	alerts, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid alerts feed %s: %v", feed, err)
+	}
+	return alerts, nil
+}
+
+// LoadAll reads every feed, returning what it could along with the errors.
+func LoadAll(ctx context.Context, feeds []string) ([]Alert, []error) {
+	var all []Alert
+	var errs []error
+	for _, feed := range feeds {
+		alerts, err := Load(ctx, feed)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		all = append(all, alerts...)
+	}
+	return all, errs
+}
+
+func fetch(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("status: %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Parse accepts a GTFS-realtime FeedMessage in JSON form (either field
+// naming) or the simple format:
+//
+//	{"alerts": [{"effect": "DETOUR", "header": "...", "lines": ["545"],
+//	  "stops": ["Westlake Station"], "start": "2025-01-02T18:00:00-08:00"}]}
+//
+// Protobuf feeds, which is how most agencies serve GTFS-realtime, are
+// rejected; they need converting to JSON first.
+func Parse(data []byte) ([]Alert, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return nil, fmt.Errorf("not JSON (protobuf GTFS-realtime feeds must be converted to JSON first)")
+	}
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &probe); err != nil {
+		return nil, err
+	}
+	if _, ok := probe["entity"]; ok {
+		return parseGTFSRealtime(data)
+	}
+	if _, ok := probe["alerts"]; ok {
+		return parseSimple(data)
+	}
+	return nil, fmt.Errorf("expected a GTFS-realtime \"entity\" list or an \"alerts\" list")
+}
+
+type simpleFeed struct {
+	Alerts []struct {
+		ID          string    `json:"id"`
+		Effect      string    `json:"effect"`
+		Header      string    `json:"header"`
+		Description string    `json:"description"`
+		URL         string    `json:"url"`
+		Lines       []string  `json:"lines"`
+		Stops       []string  `json:"stops"`
+		Start       time.Time `json:"start"`
+		End         time.Time `json:"end"`
+	} `json:"alerts"`
+}
+
+func parseSimple(data []byte) ([]Alert, error) {
+	var feed simpleFeed
+	if err := json.Unmarshal(data, &feed); err != nil {
+		return nil, err
+	}
+
+	alerts := make([]Alert, 0, len(feed.Alerts))
+	for _, a := range feed.Alerts {
+		alert := Alert{
+			ID:          a.ID,
+			Effect:      strings.ToUpper(a.Effect),
+			Header:      a.Header,
+			Description: a.Description,
+			URL:         a.URL,
+			Lines:       a.Lines,
+			Stops:       a.Stops,
+		}
+		if !a.Start.IsZero() || !a.End.IsZero() {
+			alert.Periods = []Period{{Start: a.Start, End: a.End}}
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, nil
+}
+
+// Rename maps feed route and stop IDs to the names Google uses, for feeds
+// whose IDs aren't the public line numbers or stop names.
+func Rename(alerts []Alert, routes, stops map[string]string) {
+	for i := range alerts {
+		for j, line := range alerts[i].Lines {
+			if name, ok := routes[line]; ok {
+				alerts[i].Lines[j] = name
+			}
+		}
+		for j, stop := range alerts[i].Stops {
+			if name, ok := stops[stop]; ok {
+				alerts[i].Stops[j] = name
+			}
+		}
+	}
+}
+
+// Outages turns alerts about stations into a check for whether a stop has
+// lost step-free access at a given time. Only ACCESSIBILITY_ISSUE alerts
+// and alerts mentioning an elevator count; a closed stop isn't an elevator
+// outage.
+func Outages(alerts []Alert) func(stop string, t time.Time) bool {
+	return func(stop string, t time.Time) bool {
+		for _, a := range alerts {
+			if !a.StepFreeIssue() || !a.ActiveAt(t) {
+				continue
+			}
+			for _, s := range a.Stops {
+				if strings.EqualFold(s, stop) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// StepFreeIssue reports whether a is about step-free access.
+func (a Alert) StepFreeIssue() bool {
+	if a.Effect == "ACCESSIBILITY_ISSUE" {
+		return true
+	}
+	text := strings.ToLower(a.Header + " " + a.Description)
+	return strings.Contains(text, "elevator")
+}
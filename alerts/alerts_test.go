@@ -0,0 +1,216 @@
+package alerts
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"seattle-commute-cli/transit"
+)
+
+func loadFixture(t *testing.T, name string) []Alert {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alerts, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse(%s): %v", name, err)
+	}
+	return alerts
+}
+
+func TestParseGTFSRealtime(t *testing.T) {
+	alerts := loadFixture(t, "gtfsrt.json")
+	if len(alerts) != 2 {
+		t.Fatalf("got %d alerts, want 2", len(alerts))
+	}
+
+	detour := alerts[0]
+	if detour.ID != "detour-545" || detour.Effect != "DETOUR" {
+		t.Errorf("got %s/%s, want detour-545/DETOUR", detour.ID, detour.Effect)
+	}
+	if detour.Header != "Route 545 detoured off Stewart St" {
+		t.Errorf("header = %q", detour.Header)
+	}
+	if detour.Description != "Use temporary stops on Olive Way." {
+		t.Errorf("description = %q", detour.Description)
+	}
+	if len(detour.Lines) != 1 || detour.Lines[0] != "545" {
+		t.Errorf("lines = %v, want [545]", detour.Lines)
+	}
+	// activePeriod.start is a quoted timestamp in protobuf JSON
+	if len(detour.Periods) != 1 || !detour.Periods[0].Start.Equal(time.Unix(1735776000, 0)) || !detour.Periods[0].End.IsZero() {
+		t.Errorf("periods = %+v", detour.Periods)
+	}
+
+	link := alerts[1]
+	if len(link.Lines) != 1 || link.Lines[0] != "1 Line" {
+		t.Errorf("lines = %v, want [1 Line]", link.Lines)
+	}
+	if len(link.Stops) != 1 || link.Stops[0] != "Westlake Station" {
+		t.Errorf("stops = %v, want [Westlake Station]", link.Stops)
+	}
+	// An untagged translation counts as English
+	if !strings.HasPrefix(link.Header, "1 Line single-tracking") {
+		t.Errorf("header = %q", link.Header)
+	}
+	if len(link.Periods) != 0 {
+		t.Errorf("periods = %+v, want none", link.Periods)
+	}
+}
+
+func TestParseSimple(t *testing.T) {
+	alerts := loadFixture(t, "simple.json")
+	if len(alerts) != 1 {
+		t.Fatalf("got %d alerts, want 1", len(alerts))
+	}
+	a := alerts[0]
+	if a.Effect != "STOP_MOVED" || len(a.Stops) != 1 || a.Stops[0] != "Pine St & 4th Ave" {
+		t.Errorf("got %+v", a)
+	}
+	if len(a.Periods) != 1 || a.Periods[0].Start.IsZero() || a.Periods[0].End.IsZero() {
+		t.Errorf("periods = %+v", a.Periods)
+	}
+}
+
+func TestParseRejectsProtobuf(t *testing.T) {
+	_, err := Parse([]byte{0x0a, 0x0d, 0x0a, 0x03, '2', '.', '0'})
+	if err == nil || !strings.Contains(err.Error(), "protobuf") {
+		t.Errorf("err = %v, want a protobuf error", err)
+	}
+}
+
+func TestTranslationPicking(t *testing.T) {
+	var text translatedText
+	text.Translation = append(text.Translation,
+		struct {
+			Text     string `json:"text"`
+			Language string `json:"language"`
+		}{Text: "Desvío", Language: "es"},
+		struct {
+			Text     string `json:"text"`
+			Language string `json:"language"`
+		}{Text: "Detour", Language: "en-US"},
+	)
+	if got := text.English(); got != "Detour" {
+		t.Errorf("English() = %q, want Detour", got)
+	}
+	text.Translation = text.Translation[:1]
+	if got := text.English(); got != "Desvío" {
+		t.Errorf("English() = %q, want the first translation", got)
+	}
+}
+
+func TestToSnake(t *testing.T) {
+	for in, want := range map[string]string{
+		"informedEntity": "informed_entity",
+		"routeId":        "route_id",
+		"effect":         "effect",
+		"route_id":       "route_id",
+	} {
+		if got := toSnake(in); got != want {
+			t.Errorf("toSnake(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestActiveAt(t *testing.T) {
+	start := time.Date(2025, 1, 2, 18, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+	a := Alert{Periods: []Period{{Start: start, End: end}}}
+
+	cases := []struct {
+		at   time.Time
+		want bool
+	}{
+		{start.Add(-time.Second), false},
+		{start, true},
+		{end, true},
+		{end.Add(time.Second), false},
+	}
+	for _, c := range cases {
+		if got := a.ActiveAt(c.at); got != c.want {
+			t.Errorf("ActiveAt(%s) = %v, want %v", c.at, got, c.want)
+		}
+	}
+
+	if !(Alert{}).ActiveAt(start) {
+		t.Error("an alert without periods should always be active")
+	}
+	open := Alert{Periods: []Period{{Start: start}}}
+	if !open.ActiveAt(start.AddDate(1, 0, 0)) {
+		t.Error("an open-ended period should stay active")
+	}
+}
+
+func TestAffects(t *testing.T) {
+	a := Alert{Lines: []string{"545"}, Stops: []string{"Westlake Station"}}
+
+	cases := []struct {
+		step transit.Step
+		want bool
+	}{
+		{transit.Step{LineInfo: "Bus 545"}, true},
+		{transit.Step{LineInfo: "Bus 5450"}, false},
+		{transit.Step{LineInfo: "Bus 45"}, false},
+		{transit.Step{LineInfo: "Light rail 1 Line", ArrivalStop: "westlake station"}, true},
+		{transit.Step{LineInfo: "Bus 40", DepartureStop: "Westlake Station"}, true},
+		{transit.Step{LineInfo: "Bus 40", DepartureStop: "Fremont"}, false},
+	}
+	for _, c := range cases {
+		if got := a.Affects(c.step); got != c.want {
+			t.Errorf("Affects(%+v) = %v, want %v", c.step, got, c.want)
+		}
+	}
+}
+
+func TestAttach(t *testing.T) {
+	now := time.Now()
+	routes := []transit.Route{
+		{Steps: []transit.Step{
+			{Mode: "WALKING", Duration: 3 * time.Minute},
+			{Mode: "TRANSIT", LineInfo: "Bus 545", DepartTime: now},
+		}},
+		{Steps: []transit.Step{
+			{Mode: "TRANSIT", LineInfo: "Bus 40", DepartTime: now},
+		}},
+	}
+	alerts := []Alert{
+		{Effect: "DETOUR", Header: "545 detour", Lines: []string{"545"}},
+		{Effect: "DETOUR", Header: "expired", Lines: []string{"545"}, Periods: []Period{{End: now.Add(-time.Hour)}}},
+	}
+
+	Attach(routes, alerts)
+	if len(routes[0].Alerts) != 1 || routes[0].Alerts[0].Header != "545 detour" {
+		t.Errorf("route 1 alerts = %+v, want only the active 545 detour", routes[0].Alerts)
+	}
+	if len(routes[1].Alerts) != 0 {
+		t.Errorf("route 2 alerts = %+v, want none", routes[1].Alerts)
+	}
+}
+
+func TestOutages(t *testing.T) {
+	now := time.Now()
+	outage := Outages([]Alert{
+		{Effect: "ACCESSIBILITY_ISSUE", Header: "Access limited", Stops: []string{"Capitol Hill"}},
+		{Effect: "OTHER_EFFECT", Header: "Elevator out of service", Stops: []string{"Westlake"}},
+		{Effect: "STOP_MOVED", Header: "Stop closed", Stops: []string{"Pine St & 4th Ave"}},
+		{Effect: "ACCESSIBILITY_ISSUE", Header: "Elevator repair", Stops: []string{"Beacon Hill"}, Periods: []Period{{End: now.Add(-time.Hour)}}},
+	})
+
+	cases := map[string]bool{
+		"Capitol Hill":      true,
+		"westlake":          true,
+		"Pine St & 4th Ave": false,
+		"Beacon Hill":       false,
+		"Othello":           false,
+	}
+	for stop, want := range cases {
+		if got := outage(stop, now); got != want {
+			t.Errorf("outage(%q) = %v, want %v", stop, got, want)
+		}
+	}
+}
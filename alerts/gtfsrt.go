@@ -0,0 +1,160 @@
+package alerts
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// The GTFS-realtime Alert message, as JSON. Protobuf JSON encoders either
+// keep field names (active_period) or camel-case them (activePeriod);
+// parseGTFSRealtime normalizes to the former first.
+type gtfsFeed struct {
+	Entity []struct {
+		ID    string     `json:"id"`
+		Alert *gtfsAlert `json:"alert"`
+	} `json:"entity"`
+}
+
+type gtfsAlert struct {
+	ActivePeriod []struct {
+		Start unixTime `json:"start"`
+		End   unixTime `json:"end"`
+	} `json:"active_period"`
+	InformedEntity []struct {
+		RouteID string `json:"route_id"`
+		StopID  string `json:"stop_id"`
+		Trip    *struct {
+			RouteID string `json:"route_id"`
+		} `json:"trip"`
+	} `json:"informed_entity"`
+	Effect          string         `json:"effect"`
+	URL             translatedText `json:"url"`
+	HeaderText      translatedText `json:"header_text"`
+	DescriptionText translatedText `json:"description_text"`
+}
+
+type translatedText struct {
+	Translation []struct {
+		Text     string `json:"text"`
+		Language string `json:"language"`
+	} `json:"translation"`
+}
+
+// English picks the English (or untagged) translation, else the first.
+func (t translatedText) English() string {
+	for _, tr := range t.Translation {
+		if tr.Language == "" || strings.HasPrefix(strings.ToLower(tr.Language), "en") {
+			return tr.Text
+		}
+	}
+	if len(t.Translation) > 0 {
+		return t.Translation[0].Text
+	}
+	return ""
+}
+
+// unixTime is a POSIX timestamp, which protobuf JSON writes as a string.
+type unixTime int64
+
+func (u *unixTime) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		*u = 0
+		return nil
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	*u = unixTime(n)
+	return nil
+}
+
+func (u unixTime) Time() time.Time {
+	if u == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(u), 0)
+}
+
+func parseGTFSRealtime(data []byte) ([]Alert, error) {
+	var raw any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(snakeKeys(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	var feed gtfsFeed
+	if err := json.Unmarshal(data, &feed); err != nil {
+		return nil, err
+	}
+
+	var alerts []Alert
+	for _, e := range feed.Entity {
+		if e.Alert == nil {
+			continue
+		}
+		a := Alert{
+			ID:          e.ID,
+			Effect:      e.Alert.Effect,
+			Header:      e.Alert.HeaderText.English(),
+			Description: e.Alert.DescriptionText.English(),
+			URL:         e.Alert.URL.English(),
+		}
+		for _, p := range e.Alert.ActivePeriod {
+			a.Periods = append(a.Periods, Period{Start: p.Start.Time(), End: p.End.Time()})
+		}
+		for _, ie := range e.Alert.InformedEntity {
+			route := ie.RouteID
+			if route == "" && ie.Trip != nil {
+				route = ie.Trip.RouteID
+			}
+			if route != "" {
+				a.Lines = append(a.Lines, route)
+			}
+			if ie.StopID != "" {
+				a.Stops = append(a.Stops, ie.StopID)
+			}
+		}
+		alerts = append(alerts, a)
+	}
+	return alerts, nil
+}
+
+// snakeKeys rewrites camelCase object keys to snake_case, recursively.
+func snakeKeys(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, val := range v {
+			out[toSnake(k)] = snakeKeys(val)
+		}
+		return out
+	case []any:
+		for i := range v {
+			v[i] = snakeKeys(v[i])
+		}
+		return v
+	}
+	return v
+}
+
+func toSnake(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,198 @@
+package batch
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"seattle-commute-cli/transit"
+)
+
+var apiKeyPattern = regexp.MustCompile(`key=[^&"\s]+`)
+
+// Planner is the part of transit.TransitService a batch needs.
+type Planner interface {
+	GetRoutesAt(origin, destination string, departAt time.Time) ([]transit.Route, error)
+}
+
+type Row struct {
+	Line     int
+	From     string
+	To       string
+	DepartAt time.Time
+	// FromQuery/ToQuery are what gets sent to the planner, e.g. a saved
+	// place's place ID. Empty means use From/To as written.
+	FromQuery string
+	ToQuery   string
+}
+
+type Result struct {
+	From           string        `json:"from"`
+	To             string        `json:"to"`
+	DepartAt       time.Time     `json:"depart_at"`
+	BestDuration   time.Duration `json:"-"`
+	Transfers      int           `json:"transfers"`
+	WalkTime       time.Duration `json:"-"`
+	FirstDeparture time.Time     `json:"first_departure,omitzero"`
+	Lines          []string      `json:"lines,omitempty"`
+	Error          string        `json:"error,omitempty"`
+
+	// Minutes are for JSON readers that don't want to parse Go durations.
+	BestMinutes float64 `json:"best_minutes"`
+	WalkMinutes float64 `json:"walk_minutes"`
+}
+
+// ReadCSV reads rows of from,to[,time]. A first row starting with "from" is
+// treated as a header. parseTime turns the optional time column into a
+// departure time; a zero time means now.
+func ReadCSV(r io.Reader, parseTime func(string) (time.Time, error)) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %v", err)
+	}
+
+	var rows []Row
+	for i, record := range records {
+		if i == 0 && len(record) > 0 && strings.EqualFold(strings.TrimSpace(record[0]), "from") {
+			continue
+		}
+		if len(record) == 0 || (len(record) == 1 && strings.TrimSpace(record[0]) == "") {
+			continue
+		}
+		if len(record) < 2 {
+			return nil, fmt.Errorf("line %d: need at least from and to columns", i+1)
+		}
+
+		row := Row{Line: i + 1, From: strings.TrimSpace(record[0]), To: strings.TrimSpace(record[1])}
+		if len(record) > 2 && strings.TrimSpace(record[2]) != "" {
+			row.DepartAt, err = parseTime(record[2])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", i+1, err)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// Run plans every row with at most concurrency requests in flight. Results
+// come back in input order; a failed row carries its error instead of
+// stopping the batch. progress, if set, is called after each row.
+func Run(planner Planner, rows []Row, concurrency int, progress func(done, total int)) []Result {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]Result, len(rows))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = plan(planner, rows[i])
+				if progress != nil {
+					mu.Lock()
+					done++
+					progress(done, len(rows))
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for i := range rows {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+func plan(planner Planner, row Row) Result {
+	result := Result{From: row.From, To: row.To, DepartAt: row.DepartAt}
+	departAt := row.DepartAt
+	if departAt.IsZero() {
+		departAt = time.Now()
+		result.DepartAt = departAt
+	}
+
+	origin, destination := row.From, row.To
+	if row.FromQuery != "" {
+		origin = row.FromQuery
+	}
+	if row.ToQuery != "" {
+		destination = row.ToQuery
+	}
+
+	routes, err := planner.GetRoutesAt(origin, destination, departAt)
+	if err != nil {
+		// Reports get shared; don't leak the API key from request URLs
+		result.Error = apiKeyPattern.ReplaceAllString(err.Error(), "key=REDACTED")
+		return result
+	}
+	if len(routes) == 0 {
+		result.Error = "no routes found"
+		return result
+	}
+
+	best := routes[0]
+	first := routes[0].DepartureTime
+	for _, r := range routes[1:] {
+		if r.Duration < best.Duration {
+			best = r
+		}
+		if r.DepartureTime.Before(first) {
+			first = r.DepartureTime
+		}
+	}
+
+	result.BestDuration = best.Duration
+	result.BestMinutes = best.Duration.Minutes()
+	result.Transfers = best.Transfers()
+	result.WalkTime = best.WalkingTime()
+	result.WalkMinutes = result.WalkTime.Minutes()
+	result.FirstDeparture = first
+	result.Lines = best.Lines()
+	return result
+}
+
+func WriteJSON(w io.Writer, results []Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
+
+func WriteCSV(w io.Writer, results []Result) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"from", "to", "depart_at", "best_minutes", "transfers", "walk_minutes", "first_departure", "lines", "error"})
+
+	for _, r := range results {
+		record := []string{r.From, r.To, r.DepartAt.Format(time.RFC3339), "", "", "", "", strings.Join(r.Lines, " > "), r.Error}
+		if r.Error == "" {
+			record[3] = fmt.Sprintf("%.0f", r.BestMinutes)
+			record[4] = fmt.Sprintf("%d", r.Transfers)
+			record[5] = fmt.Sprintf("%.0f", r.WalkMinutes)
+			record[6] = r.FirstDeparture.Format(time.RFC3339)
+		}
+		writer.Write(record)
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Cache is a small JSON file backed key/value store for API responses we
+// don't want to pay for twice.
+type Cache struct {
+	path    string
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]entry
+	dirty   bool
+}
+
+type entry struct {
+	Value   json.RawMessage `json:"value"`
+	Created time.Time       `json:"created"`
+}
+
+func Dir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".seattle-commute", "cache")
+}
+
+// Open loads the named cache. Entries older than ttl are ignored; a zero ttl
+// keeps entries forever. A missing or corrupt cache file starts empty.
+func Open(name string, ttl time.Duration) *Cache {
+	c := &Cache{
+		path:    filepath.Join(Dir(), name+".json"),
+		ttl:     ttl,
+		entries: make(map[string]entry),
+	}
+	if data, err := os.ReadFile(c.path); err == nil {
+		json.Unmarshal(data, &c.entries)
+	}
+	return c
+}
+
+func (c *Cache) Get(key string, v any) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return false
+	}
+	if c.ttl > 0 && time.Since(e.Created) > c.ttl {
+		delete(c.entries, key)
+		c.dirty = true
+		return false
+	}
+	return json.Unmarshal(e.Value, v) == nil
+}
+
+func (c *Cache) Set(key string, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry{Value: data, Created: time.Now()}
+	c.dirty = true
+}
+
+// Save writes the cache back to disk if it changed.
+func (c *Cache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.path, data, 0600); err != nil {
+		return err
+	}
+	c.dirty = false
+	return nil
+}
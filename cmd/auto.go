@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"seattle-commute-cli/config"
+	"seattle-commute-cli/distance"
+	"seattle-commute-cli/location"
+	"seattle-commute-cli/region"
+)
+
+// How close (on foot) a detected location has to be to home or work to
+// count as being there.
+const autoNearPlaceWalk = 10 * time.Minute
+
+// autoDirection is the outcome of working out where you are and where you're
+// going without -w.
+type autoDirection struct {
+	To          string // "home" or "work"
+	Origin      string // query to route from
+	OriginLabel string
+	Reasons     []string
+	// Confident is false when there was nothing to go on and the caller
+	// should use the old defaults.
+	Confident bool
+}
+
+// decideDirection combines the location provider chain, walking distance to
+// home and work, and the configured schedule. Location evidence wins over the
+// schedule; with neither we fall back to assuming you're at work.
+func decideDirection(cfg *config.Config, reg *region.Region) autoDirection {
+	home := cfg.HomePlace()
+	work := cfg.WorkPlace()
+
+	schedule := cfg.Schedule
+	if len(schedule) == 0 {
+		schedule = config.DefaultSchedule
+	}
+	now := time.Now().In(reg.Location())
+	rule, err := config.MatchSchedule(schedule, now)
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+		rule = nil
+	}
+
+	at := ""
+	var fix *location.Fix
+	var reasons []string
+
+	chain, err := location.NewChain(cfg.Location.Providers, cfg.Location.Disabled)
+	if err == nil {
+		fix, err = chain.Locate(context.Background())
+	}
+	if err == nil && fix.Place != "" {
+		at = fix.Place
+		reasons = append(reasons, fmt.Sprintf("%s recognized you at %s", fix.Source, fix.Place))
+	} else if err == nil && fix.Confidence() >= location.ConfidenceMedium {
+		checker, _ := distance.NewDistanceChecker(cfg.GoogleAPIKey)
+		at = nearHomeOrWork(checker, fix, home, work)
+		if at != "" {
+			reasons = append(reasons, fmt.Sprintf("%s puts you within %s of %s", fix.Source, formatDuration(autoNearPlaceWalk), at))
+		}
+	} else {
+		fix = nil
+	}
+
+	switch {
+	case at == "home" && work != nil:
+		return autoDirection{To: "work", Origin: home.Query(), OriginLabel: "home", Reasons: reasons, Confident: true}
+	case at == "work":
+		return autoDirection{To: "home", Origin: work.Query(), OriginLabel: "work", Reasons: reasons, Confident: true}
+	case at != "":
+		// At some other saved place
+		p, _ := cfg.LookupPlace(at)
+		to := "home"
+		if rule != nil && rule.To == "work" && work != nil {
+			to = "work"
+			reasons = append(reasons, "schedule "+rule.String())
+		}
+		return autoDirection{To: to, Origin: p.Query(), OriginLabel: at, Reasons: reasons, Confident: true}
+	case fix != nil:
+		// Confidently somewhere that isn't a saved place
+		to := "home"
+		if rule != nil && rule.To == "work" && work != nil {
+			to = "work"
+			reasons = append(reasons, "schedule "+rule.String())
+		}
+		reasons = append(reasons, fmt.Sprintf("located by %s", fix.Source))
+		return autoDirection{To: to, Origin: fix.String(), OriginLabel: "your current location", Reasons: reasons, Confident: true}
+	}
+
+	if rule != nil && (rule.To == "home" || work != nil) {
+		reasons = append(reasons, "schedule "+rule.String())
+		if rule.To == "work" {
+			return autoDirection{To: "work", Origin: home.Query(), OriginLabel: "home", Reasons: reasons, Confident: true}
+		}
+		if work != nil {
+			return autoDirection{To: "home", Origin: work.Query(), OriginLabel: "work", Reasons: reasons, Confident: true}
+		}
+	}
+
+	// Nothing to go on: the caller uses the old defaults
+	return autoDirection{Reasons: []string{"location unknown and no schedule match"}}
+}
+
+// nearHomeOrWork returns "home" or "work" if fix is within a short walk of
+// it, preferring whichever is closer.
+func nearHomeOrWork(checker *distance.DistanceChecker, fix *location.Fix, home, work *config.Place) string {
+	best := ""
+	bestTime := autoNearPlaceWalk + 1
+	for name, p := range map[string]*config.Place{"home": home, "work": work} {
+		if p == nil || checker == nil {
+			continue
+		}
+		walkTime, _, err := checker.GetWalkingDistance(fix.String(), p.Query())
+		if err == nil && walkTime <= autoNearPlaceWalk && walkTime < bestTime {
+			best, bestTime = name, walkTime
+		}
+	}
+	return best
+}
+
+func (d autoDirection) Explain() string {
+	return strings.Join(d.Reasons, "; ")
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"googlemaps.github.io/maps"
+	"seattle-commute-cli/alerts"
+	"seattle-commute-cli/batch"
+	"seattle-commute-cli/config"
+	"seattle-commute-cli/transit"
+)
+
+var (
+	batchOut         string
+	batchFormat      string
+	batchConcurrency int
+	batchRate        int
+)
+
+var batchCmd = &cobra.Command{
+	Use:   "batch <input.csv>",
+	Short: "Plan many origin/destination pairs from a CSV",
+	Long:  "Read rows of from,to[,time] and report the best transit duration, transfers, walking time and first departure for each, e.g. to evaluate an office move across a team",
+	Example: `  commute batch team.csv --out report.csv
+  commute batch team.csv --format json --concurrency 8`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		if cfg.GoogleAPIKey == "" {
+			fmt.Println("❌ Configuration not found. Run 'commute init' to set up.")
+			os.Exit(1)
+		}
+
+		format := strings.ToLower(batchFormat)
+		if format == "" {
+			format = "csv"
+			if strings.EqualFold(filepath.Ext(batchOut), ".json") {
+				format = "json"
+			}
+		}
+		if format != "csv" && format != "json" {
+			fmt.Printf("❌ Unknown format %q (use csv or json)\n", batchFormat)
+			os.Exit(1)
+		}
+
+		input, err := os.Open(args[0])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer input.Close()
+
+		loc := loadRegion(cfg).Location()
+		rows, err := batch.ReadCSV(input, func(s string) (time.Time, error) {
+			return parseDepartTime(s, loc)
+		})
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		for i := range rows {
+			if rows[i].DepartAt.IsZero() {
+				rows[i].DepartAt = time.Now().In(loc)
+			}
+			rows[i].FromQuery, _ = resolvePlace(cfg, rows[i].From)
+			rows[i].ToQuery, _ = resolvePlace(cfg, rows[i].To)
+		}
+
+		access, accessOn, err := accessibility(cfg)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		service, err := transit.NewTransitService(cfg.GoogleAPIKey, maps.WithRateLimit(batchRate))
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		var planner batch.Planner = service
+		if accessOn {
+			// Warnings go to stderr too, to keep a piped report clean
+			var feeds []alerts.Alert
+			for _, urls := range [][]string{cfg.Alerts.Feeds, cfg.Accessibility.ElevatorFeeds} {
+				loaded, errs := alerts.LoadAll(context.Background(), urls)
+				for _, err := range errs {
+					fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+				}
+				feeds = append(feeds, loaded...)
+			}
+			alerts.Rename(feeds, cfg.Alerts.RouteNames, cfg.Alerts.StopNames)
+			access.Outage = alerts.Outages(feeds)
+			planner = accessiblePlanner{Planner: service, access: access}
+		}
+
+		// Progress goes to stderr so stdout can be piped
+		results := batch.Run(planner, rows, batchConcurrency, func(done, total int) {
+			fmt.Fprintf(os.Stderr, "\r🚌 Planning routes... %d/%d", done, total)
+		})
+		fmt.Fprintln(os.Stderr)
+
+		var out io.Writer = os.Stdout
+		if batchOut != "" {
+			f, err := os.Create(batchOut)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			defer f.Close()
+			out = f
+		}
+
+		if format == "json" {
+			err = batch.WriteJSON(out, results)
+		} else {
+			err = batch.WriteCSV(out, results)
+		}
+		if err != nil {
+			fmt.Printf("Error writing report: %v\n", err)
+			os.Exit(1)
+		}
+
+		failed := 0
+		for _, r := range results {
+			if r.Error != "" {
+				failed++
+			}
+		}
+		if batchOut != "" {
+			fmt.Fprintf(os.Stderr, "✅ Wrote %d results to %s", len(results), batchOut)
+			if failed > 0 {
+				fmt.Fprintf(os.Stderr, " (%d failed)", failed)
+			}
+			fmt.Fprintln(os.Stderr)
+		}
+	},
+}
+
+// accessiblePlanner drops routes that don't suit an accessibility profile,
+// so each row reports the best route the profile allows.
+type accessiblePlanner struct {
+	batch.Planner
+	access transit.Accessibility
+}
+
+func (p accessiblePlanner) GetRoutesAt(origin, destination string, departAt time.Time) ([]transit.Route, error) {
+	routes, err := p.Planner.GetRoutesAt(origin, destination, departAt)
+	if err != nil {
+		return nil, err
+	}
+	ok, problems := p.access.Filter(routes)
+	if len(ok) == 0 && len(routes) > 0 {
+		return nil, fmt.Errorf("none of the %d routes suit the %s profile (%s)", len(routes), p.access.Profile, summarizeProblems(problems))
+	}
+	return ok, nil
+}
+
+func init() {
+	batchCmd.Flags().StringVarP(&batchOut, "out", "o", "", "Write the report to a file instead of stdout")
+	batchCmd.Flags().StringVar(&batchFormat, "format", "", "Report format: csv or json (default from --out extension, else csv)")
+	batchCmd.Flags().IntVar(&batchConcurrency, "concurrency", 4, "Routes planned in parallel")
+	batchCmd.Flags().IntVar(&batchRate, "rate", 10, "Maximum API requests per second")
+	batchCmd.Flags().StringVar(&accessFlag, "access", "", "Accessibility profile: wheelchair, no-stairs, limited-walking (or none)")
+	rootCmd.AddCommand(batchCmd)
+}
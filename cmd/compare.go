@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"googlemaps.github.io/maps"
+	"seattle-commute-cli/config"
+	"seattle-commute-cli/distance"
+)
+
+// modeLabels are icon and name. The icon gets its own column because
+// emoji are two cells wide but padded as one rune by fmt.
+var modeLabels = map[maps.Mode][2]string{
+	maps.TravelModeWalking:   {"🚶", "Walk"},
+	maps.TravelModeBicycling: {"🚲", "Bike"},
+	maps.TravelModeTransit:   {"🚌", "Transit"},
+	maps.TravelModeDriving:   {"🚗", "Drive"},
+}
+
+func costModel(cfg *config.Config) distance.CostModel {
+	costs := distance.DefaultCostModel
+	if cfg.Costs.DrivingPerMile > 0 {
+		costs.DrivingPerMile = cfg.Costs.DrivingPerMile
+	}
+	if cfg.Costs.Parking > 0 {
+		costs.DrivingFixed = cfg.Costs.Parking
+	}
+	if cfg.Costs.TransitFare > 0 {
+		costs.TransitFare = cfg.Costs.TransitFare
+	}
+	return costs
+}
+
+func printComparison(results []distance.ModeResult) {
+	fastest := -1
+	for i, r := range results {
+		if r.Err == nil && (fastest < 0 || r.Duration < results[fastest].Duration) {
+			fastest = i
+		}
+	}
+
+	fmt.Printf("\n   %-9s %-8s %-10s %s\n", "Mode", "Time", "Distance", "Cost")
+	fmt.Println(strings.Repeat("-", 40))
+	for i, r := range results {
+		icon, name := modeLabels[r.Mode][0], modeLabels[r.Mode][1]
+		if r.Err != nil {
+			fmt.Printf("%s %-9s %s\n", icon, name, "unavailable")
+			continue
+		}
+		marker := ""
+		if i == fastest {
+			marker = "  ⚡ fastest"
+		}
+		fmt.Printf("%s %-9s %-8s %-10s $%.2f%s\n", icon, name, formatDuration(r.Duration), r.Distance, r.Cost, marker)
+	}
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"seattle-commute-cli/config"
+	"seattle-commute-cli/region"
+	"seattle-commute-cli/validation"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage stored commute settings",
+}
+
+var regeocodeCmd = &cobra.Command{
+	Use:   "regeocode",
+	Short: "Refresh stored coordinates and place IDs",
+	Long:  "Geocode home, work and saved places again and store their coordinates, place IDs and formatted addresses",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		if cfg.GoogleAPIKey == "" {
+			fmt.Println("❌ Configuration not found. Run 'commute init' to set up.")
+			os.Exit(1)
+		}
+
+		validator, err := validation.NewAddressValidator(cfg.GoogleAPIKey, nil)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		failed := 0
+		regeocode := func(name string, p *config.Place) *config.Place {
+			fmt.Printf("🔍 %s: %s... ", name, p.Address)
+			updated, err := validator.Geocode(p.Address)
+			if err != nil {
+				fmt.Printf("\n⚠️  %v\n", err)
+				failed++
+				return p
+			}
+			fmt.Printf("✅ (%.6f,%.6f)\n", updated.Lat, updated.Lng)
+			return updated
+		}
+
+		if cfg.HomeAddress != "" {
+			cfg.SetHome(regeocode("home", cfg.HomePlace()))
+		}
+		if work := cfg.WorkPlace(); work != nil {
+			cfg.SetWork(regeocode("work", work))
+		}
+		for _, name := range cfg.PlaceNames() {
+			cfg.SetPlace(name, regeocode(name, cfg.Places[name]))
+		}
+
+		if err := cfg.Save(); err != nil {
+			fmt.Printf("Error saving config: %v\n", err)
+			os.Exit(1)
+		}
+
+		if failed > 0 {
+			fmt.Printf("\n⚠️  %d place(s) could not be geocoded and kept their previous values\n", failed)
+			os.Exit(1)
+		}
+		fmt.Println("\n✅ Configuration saved!")
+	},
+}
+
+var regionCmd = &cobra.Command{
+	Use:   "region [name|path]",
+	Short: "Show or set the region",
+	Long:  "Show the current region, or switch to a built-in region, a region defined in ~/.seattle-commute/regions/<name>.json, or a region JSON file",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(args) == 1 {
+			name := args[0]
+			// Save files by absolute path so the region still loads when
+			// commute runs from another directory.
+			if lower := strings.ToLower(name); strings.HasSuffix(lower, ".json") || strings.HasSuffix(lower, ".geojson") {
+				abs, err := filepath.Abs(name)
+				if err != nil {
+					fmt.Printf("❌ %v\n", err)
+					os.Exit(1)
+				}
+				if _, err := os.Stat(abs); err != nil {
+					fmt.Printf("❌ Region file not found: %s\n", abs)
+					os.Exit(1)
+				}
+				name = abs
+			}
+			if _, err := region.Load(name); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+			cfg.Region = name
+			if err := cfg.Save(); err != nil {
+				fmt.Printf("Error saving config: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		r := loadRegion(cfg)
+		fmt.Printf("🗺️  Region: %s (%s)\n", r.DisplayName, r.Name)
+		fmt.Printf("Time zone: %s\n", r.Location())
+		fmt.Printf("Center: %s\n", r.Center)
+		if len(r.Agencies) > 0 {
+			fmt.Printf("Agencies: %s\n", strings.Join(r.Agencies, ", "))
+		}
+		if len(r.Features) > 0 {
+			names := make([]string, 0, len(r.Features))
+			for _, f := range r.Features {
+				names = append(names, f.Name)
+			}
+			fmt.Printf("Service area: %s\n", strings.Join(names, ", "))
+		}
+	},
+}
+
+// loadRegion returns the configured region, falling back to the built-in
+// default (with a warning) if it can't be loaded.
+func loadRegion(cfg *config.Config) *region.Region {
+	r, err := region.Load(cfg.Region)
+	if err == nil {
+		return r
+	}
+	fmt.Printf("⚠️  %v (using %s)\n", err, region.Default)
+	r, err = region.Load(region.Default)
+	if err != nil {
+		fmt.Printf("Error loading built-in region: %v\n", err)
+		os.Exit(1)
+	}
+	return r
+}
+
+func newAddressValidator(cfg *config.Config) (*validation.AddressValidator, error) {
+	return validation.NewAddressValidator(cfg.GoogleAPIKey, loadRegion(cfg))
+}
+
+func init() {
+	configCmd.AddCommand(regeocodeCmd)
+	configCmd.AddCommand(regionCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/spf13/cobra"
 	"seattle-commute-cli/config"
-	"seattle-commute-cli/validation"
 )
 
 var initCmd = &cobra.Command{
@@ -41,12 +40,12 @@ var initCmd = &cobra.Command{
 			cfg.GoogleAPIKey = strings.TrimSpace(apiKey)
 		}
 
-		validator, err := validation.NewAddressValidator(cfg.GoogleAPIKey)
+		validator, err := newAddressValidator(cfg)
 		if err != nil {
 			fmt.Printf("⚠️  Unable to validate addresses (API key might be invalid): %v\n", err)
 		} else {
 			fmt.Print("🔍 Validating home address... ")
-			validatedHome, err := validator.ValidateSeattleAddress(cfg.HomeAddress)
+			validatedHome, err := validator.ValidateAddress(cfg.HomeAddress)
 			if err != nil {
 				fmt.Printf("\n⚠️  %v\n", err)
 				fmt.Print("Continue anyway? (y/N): ")
@@ -56,7 +55,7 @@ var initCmd = &cobra.Command{
 					os.Exit(1)
 				}
 			} else {
-				cfg.HomeAddress = validatedHome
+				cfg.SetHome(validatedHome)
 				fmt.Println("✅")
 			}
 		}
@@ -67,7 +66,7 @@ var initCmd = &cobra.Command{
 		if workAddr != "" {
 			if validator != nil {
 				fmt.Print("🔍 Validating work address... ")
-				validatedWork, err := validator.ValidateSeattleAddress(workAddr)
+				validatedWork, err := validator.ValidateAddress(workAddr)
 				if err != nil {
 					fmt.Printf("\n⚠️  %v\n", err)
 					fmt.Print("Continue anyway? (y/N): ")
@@ -78,7 +77,7 @@ var initCmd = &cobra.Command{
 						cfg.WorkAddress = workAddr
 					}
 				} else {
-					cfg.WorkAddress = validatedWork
+					cfg.SetWork(validatedWork)
 					fmt.Println("✅")
 				}
 			} else {
@@ -96,6 +95,9 @@ var initCmd = &cobra.Command{
 		if cfg.WorkAddress != "" {
 			fmt.Printf("Work: %s\n", cfg.WorkAddress)
 		}
+		if !cfg.HomePlace().IsGeocoded() && validator != nil {
+			fmt.Println("💡 Run 'commute config regeocode' later to store coordinates for faster lookups")
+		}
 		fmt.Println("\nRun 'commute' to see routes home")
 		if cfg.WorkAddress != "" {
 			fmt.Println("Run 'commute -w' to see routes to work")
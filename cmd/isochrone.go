@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"googlemaps.github.io/maps"
+	"seattle-commute-cli/cache"
+	"seattle-commute-cli/config"
+	"seattle-commute-cli/distance"
+	"seattle-commute-cli/geo"
+	"seattle-commute-cli/isochrone"
+)
+
+var (
+	isoFrom   string
+	isoMax    time.Duration
+	isoRadius float64
+	isoStep   float64
+	isoMode   string
+	isoAt     string
+	isoOut    string
+)
+
+var isochroneCmd = &cobra.Command{
+	Use:   "isochrone --from <place> --max <duration>",
+	Short: "Map everywhere reachable within a time budget",
+	Long:  "Sample a grid around a place, plan a trip to each point with batched Distance Matrix requests, and write the reachable area as GeoJSON",
+	Example: `  commute isochrone --from work --max 30m > work-30m.geojson
+  commute isochrone --from home --max 45m --at 08:00 --radius 12000 --out home.geojson`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		if cfg.GoogleAPIKey == "" {
+			fmt.Fprintln(os.Stderr, "❌ Configuration not found. Run 'commute init' to set up.")
+			os.Exit(1)
+		}
+		if isoFrom == "" || isoMax <= 0 {
+			fmt.Fprintln(os.Stderr, "❌ Please provide --from and a positive --max")
+			os.Exit(1)
+		}
+
+		reg := loadRegion(cfg)
+		departAt, err := parseDepartTime(isoAt, reg.Location())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		place, ok := cfg.LookupPlace(isoFrom)
+		if !ok {
+			place = &config.Place{Address: isoFrom}
+			if pt, err := geo.ParseLatLng(isoFrom); err == nil {
+				place.Lat, place.Lng = pt.Lat, pt.Lng
+			}
+		}
+		if !place.HasCoordinates() {
+			validator, err := newAddressValidator(cfg)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if place, err = validator.Geocode(place.Address); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		checker, err := distance.NewDistanceChecker(cfg.GoogleAPIKey)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		router := &isochrone.MatrixRouter{
+			Checker:  checker,
+			Mode:     maps.Mode(strings.ToLower(isoMode)),
+			DepartAt: departAt,
+			Cache:    cache.Open("isochrone", 7*24*time.Hour),
+		}
+		opts := isochrone.Options{
+			Center: geo.Point{Lat: place.Lat, Lng: place.Lng},
+			Radius: isoRadius,
+			Step:   isoStep,
+			Max:    isoMax,
+		}
+
+		// Progress goes to stderr so the GeoJSON can be piped.
+		fmt.Fprintf(os.Stderr, "🗺️  Sampling %d points around %s... ", len(isochrone.Grid(opts.Center, opts.Radius, opts.Step)), place.Label())
+		result, err := isochrone.Compute(router, place.Query(), opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "\n❌ %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "✅ %d reachable within %s\n", len(result.Reachable), formatDuration(isoMax))
+
+		data, err := result.GeoJSON(fmt.Sprintf("%s within %s", place.Label(), formatDuration(isoMax)))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if isoOut == "" {
+			fmt.Println(string(data))
+			return
+		}
+		if err := os.WriteFile(isoOut, append(data, '\n'), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", isoOut, err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "✅ Wrote %s\n", isoOut)
+	},
+}
+
+func init() {
+	isochroneCmd.Flags().StringVar(&isoFrom, "from", "", "Saved place name or address to start from")
+	isochroneCmd.Flags().DurationVar(&isoMax, "max", 30*time.Minute, "Travel time budget, e.g. 30m")
+	isochroneCmd.Flags().Float64Var(&isoRadius, "radius", 10000, "How far out to sample, in meters")
+	isochroneCmd.Flags().Float64Var(&isoStep, "step", 750, "Grid spacing in meters")
+	isochroneCmd.Flags().StringVar(&isoMode, "mode", "transit", "Travel mode: transit, walking, bicycling or driving")
+	isochroneCmd.Flags().StringVar(&isoAt, "at", "", "Departure time (HH:MM or RFC 3339, default now)")
+	isochroneCmd.Flags().StringVarP(&isoOut, "out", "o", "", "Write GeoJSON to this file instead of stdout")
+	rootCmd.AddCommand(isochroneCmd)
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"seattle-commute-cli/config"
+	"seattle-commute-cli/location"
+	"seattle-commute-cli/region"
+)
+
+var locateCmd = &cobra.Command{
+	Use:   "locate",
+	Short: "Show what each location provider reports",
+	Long:  "Run every configured location provider and show its result, accuracy and confidence",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		configureLocation(cfg, loadRegion(cfg))
+
+		chain := newLocationChain(cfg)
+		ctx := context.Background()
+		for i, p := range chain.Providers {
+			fmt.Printf("%d. %-10s ", i+1, p.Name())
+			fix, err := p.Locate(ctx)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				continue
+			}
+			fmt.Printf("✅ %s", fix)
+			if fix.Accuracy > 0 {
+				fmt.Printf(" ±%.0fm", fix.Accuracy)
+			}
+			if fix.Place != "" {
+				fmt.Printf(" [%s]", fix.Place)
+			}
+			fmt.Printf(" (confidence: %s)\n", fix.Confidence())
+		}
+	},
+}
+
+// configureLocation hands the providers what they need from the config: the
+// region center for the default provider and learned networks for the
+// network provider.
+func configureLocation(cfg *config.Config, reg *region.Region) {
+	location.DefaultCenter = reg.Center.Point()
+
+	location.Networks.Command = cfg.Location.NetworkCommand
+	location.Networks.Known = nil
+	places := cfg.AllPlaces()
+	for _, name := range sortedKeys(places) {
+		p := places[name]
+		if p.Networks == nil || !p.HasCoordinates() {
+			continue
+		}
+		location.Networks.Known = append(location.Networks.Known, location.KnownNetwork{
+			Place:       name,
+			Lat:         p.Lat,
+			Lng:         p.Lng,
+			SSIDs:       p.Networks.SSIDs,
+			GatewayMACs: p.Networks.GatewayMACs,
+			Custom:      p.Networks.Custom,
+		})
+	}
+}
+
+func sortedKeys(places map[string]*config.Place) []string {
+	names := make([]string, 0, len(places))
+	for name := range places {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func newLocationChain(cfg *config.Config) *location.Chain {
+	chain, err := location.NewChain(cfg.Location.Providers, cfg.Location.Disabled)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	return chain
+}
+
+// detectLocation runs the provider chain and enforces the configured minimum
+// confidence, warning about low-confidence fixes and refusing ones below it.
+func detectLocation(cfg *config.Config) *location.Fix {
+	minConfidence, err := location.ParseConfidence(cfg.Location.MinConfidence)
+	if err != nil {
+		fmt.Printf("\nError: %v\n", err)
+		os.Exit(1)
+	}
+
+	fix, err := newLocationChain(cfg).Locate(context.Background())
+	if err != nil {
+		fmt.Printf("\nError: %v\n", err)
+		os.Exit(1)
+	}
+
+	confidence := fix.Confidence()
+	if confidence < minConfidence {
+		if fix.Fallback {
+			fmt.Printf("\n❌ Couldn't detect your location (only the configured fallback location is available)\n")
+		} else {
+			fmt.Printf("\n❌ Location from %s is too imprecise (confidence: %s, need %s)\n", fix.Source, confidence, minConfidence)
+		}
+		fmt.Println("💡 Try: 'commute --from \"your current address\"'")
+		os.Exit(1)
+	}
+
+	fmt.Println("✅")
+	switch confidence {
+	case location.ConfidenceNone:
+		fmt.Printf("⚠️  Using a default location (%s), not where you actually are\n", fix.Source)
+	case location.ConfidenceLow:
+		fmt.Printf("⚠️  Low-confidence location from %s", fix.Source)
+		if fix.Accuracy > 0 {
+			fmt.Printf(" (±%.1f km)", fix.Accuracy/1000)
+		}
+		fmt.Println(" - use --from for better results")
+	}
+	return fix
+}
+
+func init() {
+	rootCmd.AddCommand(locateCmd)
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"googlemaps.github.io/maps"
+	"seattle-commute-cli/config"
+	"seattle-commute-cli/distance"
+)
+
+var (
+	matrixFrom []string
+	matrixTo   []string
+	matrixMode string
+	matrixAt   string
+)
+
+var matrixCmd = &cobra.Command{
+	Use:   "matrix --from <places> --to <places>",
+	Short: "Travel times between several origins and destinations",
+	Long:  "Evaluate every origin against every destination in one Distance Matrix request, e.g. candidate apartments against teammates' offices",
+	Example: `  commute matrix --from "Fremont, Seattle" --from "Ballard, Seattle" --to work --to "Bellevue Square"
+  commute matrix --from home --from gym --to work --mode bicycling --at 08:30`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		if cfg.GoogleAPIKey == "" {
+			fmt.Println("❌ Configuration not found. Run 'commute init' to set up.")
+			os.Exit(1)
+		}
+		if len(matrixFrom) == 0 || len(matrixTo) == 0 {
+			fmt.Println("❌ Please provide at least one --from and one --to")
+			os.Exit(1)
+		}
+
+		reg := loadRegion(cfg)
+		departAt, err := parseDepartTime(matrixAt, reg.Location())
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		origins := make([]string, len(matrixFrom))
+		for i, from := range matrixFrom {
+			origins[i], _ = resolvePlace(cfg, from)
+		}
+		destinations := make([]string, len(matrixTo))
+		for i, to := range matrixTo {
+			destinations[i], _ = resolvePlace(cfg, to)
+		}
+
+		checker, err := distance.NewDistanceChecker(cfg.GoogleAPIKey)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("🧮 Computing %d×%d %s matrix... ", len(origins), len(destinations), matrixMode)
+		m, err := checker.GetMatrix(origins, destinations, maps.Mode(strings.ToLower(matrixMode)), departAt)
+		if err != nil {
+			fmt.Printf("\n❌ %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("✅")
+
+		printMatrix(m, matrixFrom, matrixTo)
+	},
+}
+
+func printMatrix(m *distance.Matrix, originLabels, destLabels []string) {
+	const width = 18
+
+	fmt.Printf("\n%-*s", width, "")
+	for _, label := range destLabels {
+		fmt.Printf(" %-*s", width, truncate(label, width))
+	}
+	fmt.Println()
+	fmt.Println(strings.Repeat("-", (width+1)*(len(destLabels)+1)))
+
+	for i, row := range m.Cells {
+		fmt.Printf("%-*s", width, truncate(originLabels[i], width))
+		for _, cell := range row {
+			value := "—"
+			if cell.OK() {
+				value = fmt.Sprintf("%s (%s)", formatDuration(cell.Duration), cell.Distance)
+			}
+			fmt.Printf(" %-*s", width, value)
+		}
+		fmt.Println()
+	}
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// parseDepartTime accepts "" or "now", a clock time like "08:30" (the next
+// time that clock time comes round), or RFC 3339. A zero time means now.
+func parseDepartTime(s string, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.EqualFold(s, "now") {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"15:04", "3:04PM", "3:04pm", "3PM", "3pm"} {
+		clock, err := time.Parse(layout, strings.ReplaceAll(s, " ", ""))
+		if err != nil {
+			continue
+		}
+		now := time.Now().In(loc)
+		t := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+		if t.Before(now) {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use HH:MM or RFC 3339)", s)
+}
+
+func init() {
+	matrixCmd.Flags().StringArrayVar(&matrixFrom, "from", nil, "Origin address or saved place name (repeat for more)")
+	matrixCmd.Flags().StringArrayVar(&matrixTo, "to", nil, "Destination address or saved place name (repeat for more)")
+	matrixCmd.Flags().StringVar(&matrixMode, "mode", "transit", "Travel mode: transit, walking, bicycling or driving")
+	matrixCmd.Flags().StringVar(&matrixAt, "at", "", "Departure time (HH:MM or RFC 3339, default now)")
+	rootCmd.AddCommand(matrixCmd)
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"seattle-commute-cli/config"
+	"seattle-commute-cli/location"
+)
+
+var placesCmd = &cobra.Command{
+	Use:   "places",
+	Short: "Manage saved places",
+}
+
+var placesAddCmd = &cobra.Command{
+	Use:   "add <name> <address>",
+	Short: "Save a named place",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		name := strings.ToLower(args[0])
+		if name == "home" || name == "work" {
+			fmt.Println("❌ Use 'commute init' to change your home or work address")
+			os.Exit(1)
+		}
+
+		address := strings.Join(args[1:], " ")
+		place := &config.Place{Address: address}
+
+		validator, err := newAddressValidator(cfg)
+		if err != nil {
+			fmt.Printf("⚠️  Unable to validate addresses (API key might be invalid): %v\n", err)
+		} else {
+			fmt.Printf("🔍 Validating %s... ", name)
+			validated, err := validator.ValidateAddress(address)
+			if err != nil {
+				fmt.Printf("\n⚠️  %v\n", err)
+			} else {
+				place = validated
+				fmt.Println("✅")
+			}
+		}
+
+		cfg.SetPlace(name, place)
+		if err := cfg.Save(); err != nil {
+			fmt.Printf("Error saving config: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Saved %s: %s\n", name, place.Label())
+	},
+}
+
+var placesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved places",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		printPlace := func(name string, p *config.Place) {
+			fmt.Printf("%-10s %s", name, p.Label())
+			if p.HasCoordinates() {
+				fmt.Printf(" (%.6f,%.6f)", p.Lat, p.Lng)
+			}
+			fmt.Println()
+		}
+
+		if cfg.HomeAddress != "" {
+			printPlace("home", cfg.HomePlace())
+		}
+		if work := cfg.WorkPlace(); work != nil {
+			printPlace("work", work)
+		}
+		for _, name := range cfg.PlaceNames() {
+			printPlace(name, cfg.Places[name])
+		}
+	},
+}
+
+var placesRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a saved place",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		if !cfg.RemovePlace(args[0]) {
+			fmt.Printf("❌ No saved place named %q\n", args[0])
+			os.Exit(1)
+		}
+
+		if err := cfg.Save(); err != nil {
+			fmt.Printf("Error saving config: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Removed %s\n", args[0])
+	},
+}
+
+var placesLearnCmd = &cobra.Command{
+	Use:   "learn <name>",
+	Short: "Remember the current network as being at a place",
+	Long:  "Record the current Wi-Fi SSID, gateway MAC and custom network ID so the place can be recognized automatically next time",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		name := strings.ToLower(args[0])
+		place, ok := cfg.LookupPlace(name)
+		if !ok {
+			fmt.Printf("❌ No saved place named %q\n", args[0])
+			os.Exit(1)
+		}
+
+		location.Networks.Command = cfg.Location.NetworkCommand
+		fp := location.Networks.Current(context.Background())
+		if fp.IsEmpty() {
+			fmt.Println("❌ Couldn't read the current network (no SSID, gateway or custom ID found)")
+			os.Exit(1)
+		}
+
+		if place.Networks == nil {
+			place.Networks = &config.Networks{}
+		}
+		place.Networks.SSIDs = appendUnique(place.Networks.SSIDs, fp.SSID)
+		place.Networks.GatewayMACs = appendUnique(place.Networks.GatewayMACs, fp.GatewayMAC)
+		place.Networks.Custom = appendUnique(place.Networks.Custom, fp.Custom)
+
+		switch name {
+		case "home":
+			cfg.SetHome(place)
+		case "work":
+			cfg.SetWork(place)
+		default:
+			cfg.SetPlace(name, place)
+		}
+
+		if err := cfg.Save(); err != nil {
+			fmt.Printf("Error saving config: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Learned network for %s\n", name)
+		if fp.SSID != "" {
+			fmt.Printf("   Wi-Fi: %s\n", fp.SSID)
+		}
+		if fp.GatewayMAC != "" {
+			fmt.Printf("   Gateway: %s\n", fp.GatewayMAC)
+		}
+		if fp.Custom != "" {
+			fmt.Printf("   Custom: %s\n", fp.Custom)
+		}
+		if !place.HasCoordinates() {
+			fmt.Println("⚠️  This place has no coordinates yet; run 'commute config regeocode' so it can be recognized")
+		}
+	},
+}
+
+// resolvePlace maps a saved place name to its query and label; anything
+// else is used as an address as-is.
+func resolvePlace(cfg *config.Config, nameOrAddress string) (query, label string) {
+	if p, ok := cfg.LookupPlace(nameOrAddress); ok {
+		return p.Query(), p.Label()
+	}
+	return nameOrAddress, nameOrAddress
+}
+
+func appendUnique(list []string, value string) []string {
+	if value == "" {
+		return list
+	}
+	for _, v := range list {
+		if strings.EqualFold(v, value) {
+			return list
+		}
+	}
+	return append(list, value)
+}
+
+func init() {
+	placesCmd.AddCommand(placesAddCmd)
+	placesCmd.AddCommand(placesListCmd)
+	placesCmd.AddCommand(placesRemoveCmd)
+	placesCmd.AddCommand(placesLearnCmd)
+	rootCmd.AddCommand(placesCmd)
+}
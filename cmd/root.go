@@ -1,16 +1,28 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/spf13/cobra"
+	"googlemaps.github.io/maps"
+	"seattle-commute-cli/alerts"
 	"seattle-commute-cli/config"
 	"seattle-commute-cli/distance"
+	"seattle-commute-cli/fare"
+	"seattle-commute-cli/geocode"
+	"seattle-commute-cli/history"
+	"seattle-commute-cli/itinerary"
 	"seattle-commute-cli/location"
+	"seattle-commute-cli/region"
 	"seattle-commute-cli/transit"
+	"seattle-commute-cli/trips"
 )
 
 var (
@@ -18,12 +30,28 @@ var (
 	atHome      bool
 	atWork      bool
 	workFlag    bool
+	autoFlag    bool
+
+	maxWalkFlag     time.Duration
+	arrivedFlag     time.Duration
+	showTransitFlag bool
+	walkProfileFlag string
+	compareFlag     bool
+	hubsFlag        bool
+
+	modesFlag          []string
+	fewerTransfersFlag bool
+	lessWalkingFlag    bool
+	avoidLineFlag      []string
+	preferLineFlag     []string
+	sortFlag           string
+	accessFlag         string
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "commute [from] [to]",
-	Short: "Get Seattle transit directions between locations",
-	Long:  "A CLI tool to get optimal Seattle commute routes using real-time transit data.\n\nUsage:\n  commute                           # Home from work\n  commute -w                        # Work from home\n  commute \"U District\" \"Capitol Hill\"  # Arbitrary routing",
+	Short: "Get transit directions between locations",
+	Long:  "A CLI tool to get optimal commute routes (Seattle by default) using real-time transit data.\n\nUsage:\n  commute                           # Home from work\n  commute -w                        # Work from home\n  commute -a                        # Work out the direction from location and schedule\n  commute \"U District\" \"Capitol Hill\"  # Arbitrary routing",
 	Args:  cobra.RangeArgs(0, 2),
 	Run: func(cmd *cobra.Command, args []string) {
 		cfg, err := config.LoadConfig()
@@ -32,16 +60,20 @@ var rootCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		// Determine destination and current location
-		var destination, currentLoc string
+		reg := loadRegion(cfg)
+		configureLocation(cfg, reg)
+
+		// Determine destination and current location. destination is what we
+		// print, destinationQuery/currentLoc are what we send to Google.
+		var destination, destinationQuery, currentLoc string
 		var destinationType string
 
 		if len(args) == 2 {
 			// Arbitrary routing: commute "from" "to"
-			currentLoc = args[0]
-			destination = args[1]
-			destinationType = fmt.Sprintf("destination (%s)", destination)
-			fmt.Printf("📍 Route from %s to %s\n", currentLoc, destination)
+			currentLoc, _ = resolvePlace(cfg, args[0])
+			destinationQuery, destination = resolvePlace(cfg, args[1])
+			destinationType = fmt.Sprintf("destination (%s)", args[1])
+			fmt.Printf("📍 Route from %s to %s\n", args[0], args[1])
 		} else if len(args) == 1 {
 			fmt.Printf("❌ Please provide both from and to locations, or use no arguments for home/work routing\n")
 			fmt.Printf("Example: commute \"U District Station\" \"Capitol Hill\"\n")
@@ -53,74 +85,189 @@ var rootCmd = &cobra.Command{
 				os.Exit(1)
 			}
 
-			if workFlag {
+			noOverride := !atHome && !atWork && fromAddress == ""
+
+			var auto autoDirection
+			if (autoFlag || cfg.AutoDirection) && !workFlag && noOverride {
+				fmt.Print("🧭 Working out where you're headed... ")
+				auto = decideDirection(cfg, reg)
+				fmt.Println("✅")
+				if !auto.Confident {
+					fmt.Printf("⚠️  Not sure where you are (%s), using defaults\n", auto.Explain())
+				}
+			}
+
+			// A recognized network beats assuming where you are
+			var recognized string
+			var recognizedPlace *config.Place
+			if noOverride && !auto.Confident {
+				recognized, recognizedPlace = recognizeNetwork(cfg)
+			}
+
+			if auto.Confident {
+				if auto.To == "work" {
+					destination = cfg.WorkAddress
+					destinationQuery = cfg.WorkPlace().Query()
+				} else {
+					destination = cfg.HomeAddress
+					destinationQuery = cfg.HomePlace().Query()
+				}
+				currentLoc = auto.Origin
+				destinationType = auto.To
+				fmt.Printf("📍 Going to %s from %s (%s)\n", auto.To, auto.OriginLabel, auto.Explain())
+			} else if workFlag {
 				// -w flag: going to work (assume at home)
 				if cfg.WorkAddress == "" {
 					fmt.Println("❌ Work address not configured. Run 'commute init' to set up.")
 					os.Exit(1)
 				}
 				destination = cfg.WorkAddress
-				currentLoc = cfg.HomeAddress
+				destinationQuery = cfg.WorkPlace().Query()
 				destinationType = "work"
-				fmt.Printf("📍 Going to work (assuming you're at home)\n")
+				switch recognized {
+				case "":
+					currentLoc = cfg.HomePlace().Query()
+					fmt.Printf("📍 Going to work (assuming you're at home)\n")
+				case "work":
+					fmt.Println("\n🏢 You're already at work (recognized network)")
+					return
+				default:
+					currentLoc = recognizedPlace.Query()
+					fmt.Printf("📍 Going to work from %s (recognized network)\n", recognized)
+				}
 			} else {
 				// Default: going home (assume at work)
 				destination = cfg.HomeAddress
-				if cfg.WorkAddress != "" {
-					currentLoc = cfg.WorkAddress
+				destinationQuery = cfg.HomePlace().Query()
+				if recognized == "home" {
+					fmt.Println("\n🏠 You're already at home (recognized network)")
+					if cfg.WorkAddress != "" {
+						fmt.Println("💡 Run 'commute -w' for routes to work")
+					}
+					return
+				} else if recognized != "" {
+					currentLoc = recognizedPlace.Query()
+					destinationType = "home"
+					fmt.Printf("📍 Going home from %s (recognized network)\n", recognized)
+				} else if cfg.WorkAddress != "" {
+					currentLoc = cfg.WorkPlace().Query()
 					destinationType = "home"
 					fmt.Printf("📍 Going home (assuming you're at work)\n")
 				} else {
 					// No work address configured, fall back to IP detection
 					fmt.Print("📍 Getting your current location... ")
-					currentLoc, err = location.GetCurrentLocation()
-					if err != nil {
-						fmt.Printf("\nError: %v\n", err)
-						os.Exit(1)
-					}
-					fmt.Printf("✅ (detected: %s)\n", currentLoc)
+					fix := detectLocation(cfg)
+					var label string
+					currentLoc, label = describeLocation(cfg, fix)
+					fmt.Printf("📍 Going home from %s\n", label)
 					destinationType = "home"
 				}
 			}
 
 			// Override logic for explicit location flags (only for home/work mode)
 			if atHome {
-				currentLoc = cfg.HomeAddress
+				currentLoc = cfg.HomePlace().Query()
 				fmt.Printf("📍 Override: using home as current location\n")
 			} else if atWork && cfg.WorkAddress != "" {
-				currentLoc = cfg.WorkAddress
+				currentLoc = cfg.WorkPlace().Query()
 				fmt.Printf("📍 Override: using work as current location\n")
 			} else if fromAddress != "" {
-				currentLoc = fromAddress
+				currentLoc, _ = resolvePlace(cfg, fromAddress)
 				fmt.Printf("📍 Override: using specified location: %s\n", fromAddress)
 			}
 		}
 
+		prefs, err := routePreferences(cmd, cfg, reg.Location())
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		access, accessOn, err := accessibility(cfg)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if accessOn {
+			// The closest hint the Directions API has to step-free routing
+			prefs.LessWalking = true
+		}
+		policy := transferPolicy(cfg)
+		fares, category, err := loadFareTable(cfg, reg)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		ranker, err := routeRanker(cfg, prefs)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		ranker.Reliability = policy.Reliability
+
+		distanceChecker, err := distance.NewDistanceChecker(cfg.GoogleAPIKey)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if compareFlag {
+			if accessOn {
+				fmt.Printf("❌ --compare only has overall times, so it can't check routes against the %s profile (use --access none to compare anyway)\n", access.Profile)
+				os.Exit(1)
+			}
+			fmt.Print("🔀 Comparing walk, bike, transit and drive... ")
+			results := distanceChecker.CompareModes(currentLoc, destinationQuery, costModel(cfg))
+			fmt.Println("✅")
+			fmt.Printf("\n🏠 Getting to %s (%s)\n", destination, destinationType)
+			printComparison(results)
+			return
+		}
+
 		// Check if already within walking distance
 		fmt.Print("📏 Checking distance... ")
-		distanceChecker, err := distance.NewDistanceChecker(cfg.GoogleAPIKey)
+		walkProfileName := walkProfileFlag
+		if accessOn && walkProfileName == "" && cfg.Walking.Profile == "" {
+			walkProfileName = "mobility"
+		}
+		maxWalk, arrived, walkProfile, err := cfg.Walking.WalkThresholds(time.Now().In(reg.Location()).Month(), walkProfileName)
 		if err != nil {
 			fmt.Printf("\nError: %v\n", err)
 			os.Exit(1)
 		}
+		// baseWalk is the cap before any profile, to tell when a profile is
+		// what rules the walk out.
+		baseWalk, _, _, _ := cfg.Walking.WalkThresholds(0, "none")
+		if cmd.Flags().Changed("max-walk") {
+			maxWalk, baseWalk = maxWalkFlag, maxWalkFlag
+		}
+		if cmd.Flags().Changed("arrived") {
+			arrived = arrivedFlag
+		}
+		alwaysShowTransit := showTransitFlag || cfg.Walking.AlwaysShowTransit
 
-
-		isWalkable, walkTime, walkDistance, err := distanceChecker.IsWithinWalkingDistance(currentLoc, destination)
+		isWalkable, walkTime, walkDistance, err := distanceChecker.IsWithinWalkingDistance(currentLoc, destinationQuery, maxWalk)
 		if err == nil && isWalkable {
 			fmt.Println("✅")
 
-			if walkTime <= 2*time.Minute {
+			if walkTime <= arrived {
 				fmt.Printf("\n🏠 You're already at %s!\n", destinationType)
 				fmt.Printf("📍 Current location matches your %s address\n", destinationType)
 				return
-			} else {
-				fmt.Printf("\n🚶‍♂️ You're already close to %s!\n", destinationType)
-				fmt.Printf("Walking time: %s (%s)\n", formatDuration(walkTime), walkDistance)
-				fmt.Printf("💡 No transit needed - just walk!\n")
+			}
+
+			fmt.Printf("\n🚶‍♂️ You're already close to %s!\n", destinationType)
+			fmt.Printf("Walking time: %s (%s)\n", formatDuration(walkTime), walkDistance)
+			if !alwaysShowTransit {
+				fmt.Printf("💡 No transit needed - just walk! (use --show-transit to see buses anyway)\n")
 				return
 			}
+			fmt.Println()
+		} else {
+			fmt.Println("✅")
+			if err == nil && walkProfile != "" && walkTime > maxWalk && walkTime <= baseWalk {
+				fmt.Printf("%s %s walk, but the %s profile caps walking at %s - showing transit\n", walkProfileIcon(walkProfile), formatDuration(walkTime), walkProfile, formatDuration(maxWalk))
+			}
 		}
-		fmt.Println("✅")
 
 		fmt.Print("🚌 Finding transit routes... ")
 		service, err := transit.NewTransitService(cfg.GoogleAPIKey)
@@ -128,17 +275,18 @@ var rootCmd = &cobra.Command{
 			fmt.Printf("\nError: %v\n", err)
 			os.Exit(1)
 		}
+		service.SetPreferences(prefs)
 
-		routes, err := service.GetNextRoutes(currentLoc, destination, 2)
+		routes, err := service.GetNextRoutes(currentLoc, destinationQuery, 2)
 		if err != nil {
 			if strings.Contains(err.Error(), "no routes found") {
 				fmt.Printf("\n❌ No transit routes found. This could mean:\n")
-				fmt.Printf("   • No transit service at this time (most Seattle buses run 5 AM - 2 AM)\n")
-				fmt.Printf("   • Your location is too far from Seattle transit\n")
+				fmt.Printf("   • No transit service at this time (%s)\n", reg.ServiceHoursHint())
+				fmt.Printf("   • Your location is too far from %s transit\n", reg.DisplayName)
 				fmt.Printf("   • Try running 'commute init' to update your addresses\n")
 			} else if strings.Contains(err.Error(), "ZERO_RESULTS") {
 				fmt.Printf("\n❌ No routes found between these locations.\n")
-				fmt.Printf("   • Check that both addresses are in the Seattle area\n")
+				fmt.Printf("   • Check that both addresses are in the %s area\n", reg.DisplayName)
 				fmt.Printf("   • Transit might not be available at this time\n")
 			} else {
 				fmt.Printf("\n❌ Transit service error: %v\n", err)
@@ -147,20 +295,180 @@ var rootCmd = &cobra.Command{
 		}
 		fmt.Println("✅")
 
+		feed := loadAlerts(cfg, cfg.Alerts.Feeds)
+		if accessOn {
+			access.Outage = loadOutages(cfg, feed)
+		}
+
+		if (hubsFlag || cfg.UseHubs) && len(cfg.Hubs) > 0 {
+			fmt.Print("🚲 Checking bike-and-ride / park-and-ride... ")
+			planner := &itinerary.Planner{Distance: distanceChecker, Transit: service, Hubs: hubsFromConfig(cfg)}
+			if accessOn {
+				planner.Accept = func(r transit.Route) bool { return len(access.Problems(r)) == 0 }
+			}
+			composed := planner.Plan(currentLoc, destinationQuery)
+			fmt.Printf("✅ (%d options)\n", len(composed))
+			routes = append(routes, composed...)
+		}
+
+		// Before Distinct, so a route the profile rules out can't knock out
+		// an accessible one as dominated.
+		if accessOn && len(routes) > 0 {
+			accessible, problems := access.Filter(routes)
+			if len(accessible) == 0 {
+				fmt.Printf("❌ None of the %d routes found suit the %s profile (%s)\n", len(routes), access.Profile, summarizeProblems(problems))
+				os.Exit(1)
+			}
+			if dropped := len(routes) - len(accessible); dropped > 0 {
+				fmt.Printf("♿ Hid %d route(s) that don't suit the %s profile (%s)\n", dropped, access.Profile, summarizeProblems(problems))
+			}
+			routes = accessible
+		}
+
+		routes = prefs.Distinct(routes)
+		transit.Ranker{Strategy: transit.ByDeparture}.Sort(routes)
+
 		if len(routes) == 0 {
 			fmt.Println("❌ No transit routes found")
 			os.Exit(1)
 		}
 
+		routes, honored := prefs.Apply(routes)
+		if !honored {
+			fmt.Println("⚠️  No routes fit your mode and line preferences, showing everything")
+		}
+		if ranker.Strategy != "" {
+			ranker.Sort(routes)
+		}
+
+		alerts.Attach(routes, feed)
+
+		// History and tracked trips share these keys so stats can line up.
+		from, to := placeName(cfg, currentLoc), placeName(cfg, destinationQuery)
+		kind := destinationType
+		if kind != "home" && kind != "work" {
+			kind = "other"
+		}
+
+		if !cfg.DisableHistory {
+			// Log the best route by score, whatever order --sort shows them in.
+			best := ranker
+			best.Strategy = transit.ByScore
+			scored := append([]transit.Route(nil), routes...)
+			best.Sort(scored)
+			entry := history.NewEntry(from, to, kind, scored)
+			if err := history.Append(entry); err != nil {
+				fmt.Printf("⚠️  Couldn't record history: %v\n", err)
+			}
+		}
+
 		fmt.Printf("\n🏠 Routes to %s (%s)\n", destination, destinationType)
 		fmt.Println("=" + strings.Repeat("=", len(destination)+12))
 
-		printRoutes(routes[:min(5, len(routes))])
+		opts := routeOptions{
+			Loc:          reg.Location(),
+			To:           to,
+			Transfers:    policy,
+			Fares:        fares,
+			FareCategory: category,
+		}
+		if destinationType == "home" || destinationType == "work" {
+			opts.DaysPerWeek = cfg.Fare.DaysPerWeek
+			if opts.DaysPerWeek <= 0 {
+				opts.DaysPerWeek = 5
+			}
+			opts.MonthlyPass = cfg.Fare.MonthlyPass
+		}
+		if recorded, err := trips.Load(); err == nil && len(recorded) > 0 {
+			opts.Reliability = trips.NewReliability(recorded, reg.Location())
+		}
+
+		shown := routes[:min(5, len(routes))]
+		printRoutes(shown, opts)
+
+		if err := trips.SaveShown(from, to, kind, shown); err != nil {
+			fmt.Printf("⚠️  Couldn't save routes for 'commute start': %v\n", err)
+		}
 	},
 }
 
-func printRoutes(routes []transit.Route) {
+// recognizeNetwork returns the saved place whose learned network we're on,
+// if any. It only looks at local network state, so it's cheap enough to run
+// on every invocation.
+func recognizeNetwork(cfg *config.Config) (string, *config.Place) {
+	if len(location.Networks.Known) == 0 {
+		return "", nil
+	}
+	fix, err := location.Networks.Locate(context.Background())
+	if err != nil {
+		return "", nil
+	}
+	p, ok := cfg.LookupPlace(fix.Place)
+	if !ok {
+		return "", nil
+	}
+	return fix.Place, p
+}
+
+// capitalize upper-cases the first letter of a place name, which may not be
+// ASCII.
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// describeLocation turns a detected fix into something readable: the saved
+// place we're standing at if one is within the snap radius, otherwise the
+// nearest address. It also returns the query to route from.
+func describeLocation(cfg *config.Config, fix *location.Fix) (query, label string) {
+	pt := fix.Point()
+	detected := fix.String()
+
+	if fix.Place != "" {
+		if p, ok := cfg.LookupPlace(fix.Place); ok {
+			return p.Query(), capitalize(fix.Place)
+		}
+	}
+
+	// A city-level fix can't tell us which building we're in.
+	if name, p, ok := cfg.NearestPlace(pt, cfg.SnapRadius()); ok && fix.Confidence() >= location.ConfidenceMedium {
+		return p.Query(), capitalize(name)
+	}
+
+	reverser, err := geocode.NewReverseGeocoder(cfg.GoogleAPIKey)
+	if err != nil {
+		return detected, detected
+	}
+	result, err := reverser.Reverse(pt)
+	if err != nil {
+		return detected, detected
+	}
+	return detected, "near " + result.String()
+}
+
+// routeOptions is what printRoutes needs besides the routes.
+type routeOptions struct {
+	Loc       *time.Location
+	Transfers transit.TransferPolicy
+	// Fares prices each route; nil skips fares.
+	Fares        *fare.Table
+	FareCategory string
+	// DaysPerWeek turns on a monthly cost projection for commutes.
+	DaysPerWeek float64
+	MonthlyPass float64
+	// Reliability adjusts ETAs by how your tracked trips to To went; nil
+	// skips it.
+	Reliability *trips.Reliability
+	To          string
+}
+
+func printRoutes(routes []transit.Route, opts routeOptions) {
 	now := time.Now()
+	loc := opts.Loc
+	var firstFare *fare.Quote
 
 	for i, route := range routes {
 		timeUntil := route.DepartureTime.Sub(now)
@@ -175,19 +483,41 @@ func printRoutes(routes []transit.Route) {
 
 		fmt.Printf("\n%d. Depart: %s (%s)%s\n",
 			i+1,
-			route.DepartureTime.Format("3:04 PM"),
+			route.DepartureTime.In(loc).Format("3:04 PM"),
 			formatDuration(timeUntil),
 			status)
 
 		fmt.Printf("   Arrive: %s (Travel: %s)\n",
-			route.ArrivalTime.Format("3:04 PM"),
+			route.ArrivalTime.In(loc).Format("3:04 PM"),
 			formatDuration(route.Duration))
 
+		if opts.Reliability != nil {
+			if factor, samples, match := opts.Reliability.Factor(route, opts.To); samples > 0 {
+				usual := route.DepartureTime.Add(time.Duration(float64(route.Duration) * factor))
+				if diff := usual.Sub(route.ArrivalTime); diff >= time.Minute || diff <= -time.Minute {
+					fmt.Printf("   Usually for you: %s (%s, from %d %s trips %s)\n", usual.In(loc).Format("3:04 PM"), signedDuration(diff), samples, trips.Period(route.DepartureTime.In(loc)), match)
+				}
+			}
+		}
+
 		fmt.Printf("   Distance: %s\n", route.Distance)
 
+		if opts.Fares != nil && len(route.TransitSteps()) > 0 {
+			if q, err := opts.Fares.Quote(route, opts.FareCategory); err == nil {
+				source := opts.Fares.Categories[q.Category]
+				if q.FromGoogle {
+					source = "Google"
+				}
+				fmt.Printf("   Fare: %s (%s)\n", q, source)
+				if firstFare == nil {
+					firstFare = &q
+				}
+			}
+		}
+
 		var transitSteps []transit.Step
 		for _, step := range route.Steps {
-			if step.Mode == "TRANSIT" {
+			if step.Mode == "TRANSIT" || step.Mode == "BICYCLING" || step.Mode == "DRIVING" {
 				transitSteps = append(transitSteps, step)
 			}
 		}
@@ -202,11 +532,264 @@ func printRoutes(routes []transit.Route) {
 			}
 			fmt.Printf("%s\n", strings.Join(lineInfos, " → "))
 		}
+
+		for _, a := range route.Alerts {
+			fmt.Printf("   %s %s\n", alertIcon(a.Effect), a.Header)
+			if a.Description != "" {
+				fmt.Printf("      %s\n", a.Description)
+			}
+		}
+
+		for _, c := range opts.Transfers.Connections(route) {
+			if !c.Tight() {
+				continue
+			}
+			fmt.Printf("   ⚠️  Tight transfer at %s: %s to get from %s to %s (want %s)", c.Stop(), formatSlack(c.Slack), c.From.LineInfo, c.To.LineInfo, formatSlack(c.Minimum))
+			if c.Chance >= 0 {
+				fmt.Printf(", ~%.0f%% chance", c.Chance*100)
+			}
+			fmt.Println()
+		}
+	}
+
+	if firstFare != nil && opts.DaysPerWeek > 0 {
+		monthly := fare.Monthly(firstFare.Total, opts.DaysPerWeek)
+		fmt.Printf("\n💳 About $%.0f/month commuting %g days a week at %s each way\n", monthly, opts.DaysPerWeek, firstFare)
+		if opts.MonthlyPass > 0 && opts.MonthlyPass < monthly {
+			fmt.Printf("   A $%.0f monthly pass would save about $%.0f\n", opts.MonthlyPass, monthly-opts.MonthlyPass)
+		}
 	}
 
 	fmt.Printf("\n📱 Tip: Add this tool to your PATH for quick access anywhere!\n")
 }
 
+// routePreferences merges the configured route preferences with the flags.
+// Time-scoped line rules are evaluated in the region's time zone.
+func routePreferences(cmd *cobra.Command, cfg *config.Config, loc *time.Location) (transit.Preferences, error) {
+	modes := cfg.Preferences.Modes
+	if cmd.Flags().Changed("modes") {
+		modes = modesFlag
+	}
+	parsed, err := transit.ParseModes(modes)
+	if err != nil {
+		return transit.Preferences{}, err
+	}
+	prefs := transit.Preferences{
+		Modes:          parsed,
+		FewerTransfers: fewerTransfersFlag || cfg.Preferences.FewerTransfers,
+		LessWalking:    lessWalkingFlag || cfg.Preferences.LessWalking,
+	}
+
+	for _, rule := range cfg.Preferences.Lines {
+		if err := rule.Validate(); err != nil {
+			return transit.Preferences{}, err
+		}
+		prefs.Lines = append(prefs.Lines, transit.LineRule{
+			Line:  rule.Line,
+			Avoid: rule.Avoid(),
+			Active: func(t time.Time) bool {
+				ok, _ := rule.Active(t.In(loc))
+				return ok
+			},
+		})
+	}
+	for _, line := range avoidLineFlag {
+		prefs.Lines = append(prefs.Lines, transit.LineRule{Line: line, Avoid: true})
+	}
+	for _, line := range preferLineFlag {
+		prefs.Lines = append(prefs.Lines, transit.LineRule{Line: line})
+	}
+	return prefs, nil
+}
+
+// routeRanker builds the ranker from --sort or the configured ranking. An
+// empty strategy leaves the preference/departure order alone.
+func routeRanker(cfg *config.Config, prefs transit.Preferences) (transit.Ranker, error) {
+	ranker := transit.Ranker{Prefs: prefs}
+
+	sortBy := cfg.Ranking.Sort
+	if sortFlag != "" {
+		sortBy = sortFlag
+	}
+	if sortBy != "" {
+		strategy, err := transit.ParseStrategy(sortBy)
+		if err != nil {
+			return ranker, err
+		}
+		ranker.Strategy = strategy
+	}
+
+	weights, err := transit.WeightsFromMap(cfg.Ranking.Weights)
+	if err != nil {
+		return ranker, err
+	}
+	ranker.Weights = weights
+	return ranker, nil
+}
+
+// transferPolicy converts the configured transfer minimums and delays.
+func transferPolicy(cfg *config.Config) transit.TransferPolicy {
+	policy := transit.TransferPolicy{
+		Minimums:   make(map[string]time.Duration),
+		MeanDelays: make(map[string]time.Duration),
+	}
+	for pair, minutes := range cfg.Transfers.MinMinutes {
+		policy.Minimums[strings.ToLower(pair)] = time.Duration(minutes * float64(time.Minute))
+	}
+	for mode, minutes := range cfg.Transfers.MeanDelayMinutes {
+		policy.MeanDelays[strings.ToLower(mode)] = time.Duration(minutes * float64(time.Minute))
+	}
+	return policy
+}
+
+// placeName returns the name of the saved place a query points at, or the
+// query itself.
+func placeName(cfg *config.Config, query string) string {
+	for name, p := range cfg.AllPlaces() {
+		if p.Query() == query {
+			return name
+		}
+	}
+	return query
+}
+
+func walkProfileIcon(profile string) string {
+	switch profile {
+	case "rain":
+		return "☔"
+	case "mobility":
+		return "♿"
+	}
+	return "🚶"
+}
+
+// loadAlerts reads alert feeds, warning about any that fail.
+func loadAlerts(cfg *config.Config, feeds []string) []alerts.Alert {
+	if len(feeds) == 0 {
+		return nil
+	}
+	feed, errs := alerts.LoadAll(context.Background(), feeds)
+	for _, err := range errs {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+	alerts.Rename(feed, cfg.Alerts.RouteNames, cfg.Alerts.StopNames)
+	return feed
+}
+
+// accessibility resolves --access or the configured accessibility profile.
+func accessibility(cfg *config.Config) (transit.Accessibility, bool, error) {
+	name := cfg.Accessibility.Profile
+	if accessFlag != "" {
+		name = accessFlag
+	}
+	if name == "" || name == "none" {
+		return transit.Accessibility{}, false, nil
+	}
+
+	access, err := transit.AccessProfile(name)
+	if err != nil {
+		return access, false, err
+	}
+	if cfg.Accessibility.MaxStepWalkMinutes > 0 {
+		access.MaxStepWalk = time.Duration(cfg.Accessibility.MaxStepWalkMinutes * float64(time.Minute))
+	}
+	return access, true, nil
+}
+
+// loadOutages checks for elevator outages in the elevator feeds and the
+// regular alert feed.
+func loadOutages(cfg *config.Config, feed []alerts.Alert) func(stop string, t time.Time) bool {
+	return alerts.Outages(append(loadAlerts(cfg, cfg.Accessibility.ElevatorFeeds), feed...))
+}
+
+// summarizeProblems lists accessibility problems, most common first.
+func summarizeProblems(problems map[string]int) string {
+	reasons := make([]string, 0, len(problems))
+	for reason := range problems {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if problems[reasons[i]] != problems[reasons[j]] {
+			return problems[reasons[i]] > problems[reasons[j]]
+		}
+		return reasons[i] < reasons[j]
+	})
+	return strings.Join(reasons, "; ")
+}
+
+// alertIcon picks an icon for a GTFS-realtime alert effect.
+func alertIcon(effect string) string {
+	switch effect {
+	case "DETOUR", "STOP_MOVED":
+		return "🚧"
+	case "NO_SERVICE":
+		return "⛔"
+	case "REDUCED_SERVICE", "SIGNIFICANT_DELAYS":
+		return "🐢"
+	}
+	return "📢"
+}
+
+// loadFareTable loads the configured fare table, or the region's, and
+// checks the rider category. A missing table just means no fares.
+func loadFareTable(cfg *config.Config, reg *region.Region) (*fare.Table, string, error) {
+	name := cfg.Fare.Table
+	if name == "" {
+		name = reg.FareTable
+	}
+	if name == "" {
+		return nil, "", nil
+	}
+
+	table, err := fare.Load(name)
+	if err != nil {
+		fmt.Printf("⚠️  %v (fares won't be shown)\n", err)
+		return nil, "", nil
+	}
+
+	category := strings.ToLower(cfg.Fare.Category)
+	if category == "" {
+		category = fare.DefaultCategory
+	}
+	if _, ok := table.Categories[category]; !ok {
+		return nil, "", fmt.Errorf("unknown fare category %q (use %s)", cfg.Fare.Category, strings.Join(table.CategoryNames(), ", "))
+	}
+	return table, category, nil
+}
+
+// signedDuration formats d as e.g. "+4m" or "-2m".
+func signedDuration(d time.Duration) string {
+	if d < 0 {
+		return "-" + formatSlack(-d)
+	}
+	return "+" + formatSlack(d)
+}
+
+// formatSlack is formatDuration for short gaps, where "now" would read
+// oddly.
+func formatSlack(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", max(int(d.Seconds()), 0))
+	}
+	return formatDuration(d)
+}
+
+func hubsFromConfig(cfg *config.Config) []itinerary.Hub {
+	hubs := make([]itinerary.Hub, 0, len(cfg.Hubs))
+	for _, h := range cfg.Hubs {
+		hub := itinerary.Hub{
+			Name:     h.Name,
+			Query:    h.Place.Query(),
+			Transfer: time.Duration(h.TransferMinutes * float64(time.Minute)),
+		}
+		for _, m := range h.Modes {
+			hub.Modes = append(hub.Modes, maps.Mode(strings.ToLower(m)))
+		}
+		hubs = append(hubs, hub)
+	}
+	return hubs
+}
+
 func formatDuration(d time.Duration) string {
 	if d < time.Minute {
 		return "now"
@@ -239,7 +822,21 @@ func Execute() {
 
 func init() {
 	rootCmd.Flags().BoolVarP(&workFlag, "work", "w", false, "Get routes to work (default: routes to home)")
+	rootCmd.Flags().BoolVarP(&autoFlag, "auto", "a", false, "Work out home vs work from your location and schedule")
 	rootCmd.Flags().StringVarP(&fromAddress, "from", "f", "", "Specify your current location instead of auto-detection")
 	rootCmd.Flags().BoolVar(&atHome, "at-home", false, "Override: you're currently at your home address")
 	rootCmd.Flags().BoolVar(&atWork, "at-work", false, "Override: you're currently at your work address")
+	rootCmd.Flags().DurationVar(&maxWalkFlag, "max-walk", config.DefaultMaxWalk, "Longest walk that replaces transit")
+	rootCmd.Flags().DurationVar(&arrivedFlag, "arrived", config.DefaultArrived, "Walks this short count as already being there")
+	rootCmd.Flags().BoolVar(&showTransitFlag, "show-transit", false, "Show transit routes even when the destination is walkable")
+	rootCmd.Flags().BoolVarP(&compareFlag, "compare", "c", false, "Compare walking, biking, transit and driving side by side")
+	rootCmd.Flags().BoolVar(&hubsFlag, "hubs", false, "Include bike-and-ride and park-and-ride options via configured hubs")
+	rootCmd.Flags().StringSliceVar(&modesFlag, "modes", nil, "Only ride these vehicles: bus, rail, tram, ferry")
+	rootCmd.Flags().BoolVar(&fewerTransfersFlag, "fewer-transfers", false, "Prefer routes with fewer transfers")
+	rootCmd.Flags().BoolVar(&lessWalkingFlag, "less-walking", false, "Prefer routes with less walking")
+	rootCmd.Flags().StringArrayVar(&avoidLineFlag, "avoid-line", nil, "Skip routes riding this line, e.g. \"Bus 8\" (repeatable)")
+	rootCmd.Flags().StringArrayVar(&preferLineFlag, "prefer-line", nil, "Rank routes riding this line higher, e.g. \"1 Line\" (repeatable)")
+	rootCmd.Flags().StringVar(&sortFlag, "sort", "", "Order routes by departure, arrival, duration, transfers or score")
+	rootCmd.Flags().StringVar(&accessFlag, "access", "", "Accessibility profile: wheelchair, no-stairs, limited-walking (or none)")
+	rootCmd.Flags().StringVar(&walkProfileFlag, "walk-profile", "", "Walking profile that lowers the max walk (rain, mobility, none)")
 }
\ No newline at end of file
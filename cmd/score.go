@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"seattle-commute-cli/config"
+	"seattle-commute-cli/distance"
+	"seattle-commute-cli/score"
+	"seattle-commute-cli/transit"
+)
+
+var scoreTargets []string
+
+var scoreCmd = &cobra.Command{
+	Use:   "score <candidate address>",
+	Short: "Score a candidate home by its commutes",
+	Long:  "Evaluate a candidate home against work and saved places at typical times (weekday 8am, 5:30pm, Saturday noon) and combine travel time, transfers, walking and frequency into one score",
+	Example: `  commute score "4500 Roosevelt Way NE, Seattle"
+  commute score "Columbia City, Seattle" --to work --to gym`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		if cfg.GoogleAPIKey == "" {
+			fmt.Println("❌ Configuration not found. Run 'commute init' to set up.")
+			os.Exit(1)
+		}
+
+		reg := loadRegion(cfg)
+		address := strings.Join(args, " ")
+		candidate, candidateLabel := address, address
+
+		validator, err := newAddressValidator(cfg)
+		if err == nil {
+			fmt.Print("🔍 Validating candidate... ")
+			if place, err := validator.ValidateAddress(address); err != nil {
+				fmt.Printf("\n%v\n", err)
+			} else {
+				candidate, candidateLabel = place.Query(), place.Label()
+				fmt.Println("✅")
+			}
+		}
+
+		names := scoreTargets
+		if len(names) == 0 {
+			names = cfg.Score.Targets
+		}
+		if len(names) == 0 {
+			if cfg.WorkAddress != "" {
+				names = append(names, "work")
+			}
+			names = append(names, cfg.PlaceNames()...)
+		}
+
+		var targets []score.Target
+		for _, name := range names {
+			p, ok := cfg.LookupPlace(name)
+			if !ok {
+				fmt.Printf("⚠️  No saved place named %q, skipping\n", name)
+				continue
+			}
+			targets = append(targets, score.Target{Name: name, Query: p.Query()})
+		}
+		if len(targets) == 0 {
+			fmt.Println("❌ Nothing to score against. Set a work address or add places with 'commute places add'.")
+			os.Exit(1)
+		}
+
+		scoreTimes := cfg.Score.Times
+		if len(scoreTimes) == 0 {
+			scoreTimes = config.DefaultScoreTimes
+		}
+		now := time.Now().In(reg.Location())
+		var times []score.When
+		for _, st := range scoreTimes {
+			at, err := config.NextTime(st.Days, st.At, now)
+			if err != nil {
+				fmt.Printf("Error: invalid score time %q: %v\n", st.Label, err)
+				os.Exit(1)
+			}
+			times = append(times, score.When{Label: st.Label, At: at, ToTarget: st.Direction != "from"})
+		}
+
+		weights, err := score.WeightsFromMap(cfg.Score.Weights)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		maxWalk, _, _, err := cfg.Walking.WalkThresholds(now.Month(), "")
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		service, err := transit.NewTransitService(cfg.GoogleAPIKey)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		checker, err := distance.NewDistanceChecker(cfg.GoogleAPIKey)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("🚌 Planning %d trips... ", len(targets)*len(times))
+		samples := score.Evaluate(service, checker, candidate, targets, times, maxWalk)
+		fmt.Println("✅")
+
+		fmt.Printf("\n🏡 %s\n", candidateLabel)
+		fmt.Println("=" + strings.Repeat("=", len(candidateLabel)+2))
+		for _, s := range samples {
+			fmt.Printf("%-10s %-18s ", truncate(s.Target, 10), s.When)
+			switch {
+			case s.Err != nil:
+				fmt.Println("❌ no route")
+			case s.Walkable:
+				fmt.Printf("%-7s 🚶 walk\n", formatDuration(s.Duration))
+			default:
+				fmt.Printf("%-7s %d transfer(s), %.0fm walking, %.0f/hr\n", formatDuration(s.Duration), s.Transfers, s.Walk.Minutes(), s.DeparturesPerHour)
+			}
+		}
+
+		result := score.Summarize(samples, weights)
+		if len(result.Components) == 0 {
+			fmt.Println("\n❌ No trips could be planned; can't score this address")
+			os.Exit(1)
+		}
+
+		fmt.Println("\nBreakdown:")
+		for _, c := range result.Components {
+			fmt.Printf("  %-14s %-10s %3.0f/100 (weight %.2f)\n", c.Name, c.Value, c.Rating, c.Weight)
+		}
+		if result.Failed > 0 {
+			fmt.Printf("  ⚠️  %d trip(s) had no route and count as worst case\n", result.Failed)
+		}
+		fmt.Printf("\n⭐ Score: %.0f/100\n", result.Score)
+	},
+}
+
+func init() {
+	scoreCmd.Flags().StringArrayVar(&scoreTargets, "to", nil, "Saved place to score against (repeat for more; default work and all saved places)")
+	rootCmd.AddCommand(scoreCmd)
+}
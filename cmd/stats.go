@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"seattle-commute-cli/config"
+	"seattle-commute-cli/history"
+	"seattle-commute-cli/trips"
+)
+
+var (
+	statsTo     string
+	statsMonths int
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show statistics from your commute history",
+	Long:  "Summarize the routes you've looked up: average commute by weekday, most-used lines, transit hours per month and how they're trending",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		loc := loadRegion(cfg).Location()
+
+		if statsTo != "" && statsTo != "home" && statsTo != "work" && statsTo != "other" {
+			fmt.Printf("❌ --to must be home, work or other, not %q\n", statsTo)
+			os.Exit(1)
+		}
+
+		entries, err := history.Load()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		var since time.Time
+		if statsMonths > 0 {
+			now := time.Now().In(loc)
+			since = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc).AddDate(0, -(statsMonths - 1), 0)
+		}
+		var selected []history.Entry
+		for _, e := range entries {
+			if statsTo != "" && e.Kind != statsTo {
+				continue
+			}
+			if e.Time.Before(since) {
+				continue
+			}
+			selected = append(selected, e)
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("📊 No commute history yet. Routes you look up are recorded in", history.Path())
+			return
+		}
+		if len(selected) == 0 {
+			fmt.Println("📊 No lookups match")
+			return
+		}
+
+		s := history.Summarize(history.Collapse(selected), loc)
+		fmt.Printf("📊 %d lookups since %s (repeats within %s count once)\n", s.Trips, selected[0].Time.In(loc).Format("Jan 2, 2006"), formatDuration(history.RepeatWindow))
+
+		fmt.Println("\nAverage by weekday:")
+		for _, day := range []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday} {
+			avg := s.ByWeekday[day]
+			if avg.Count == 0 {
+				continue
+			}
+			fmt.Printf("  %-4s %-7s (%d lookups)\n", day.String()[:3], formatDuration(avg.Mean()), avg.Count)
+		}
+
+		if len(s.Lines) > 0 {
+			fmt.Println("\nMost-used lines:")
+			for _, lc := range s.Lines[:min(5, len(s.Lines))] {
+				fmt.Printf("  %-22s %d\n", truncate(lc.Line, 22), lc.Count)
+			}
+		}
+
+		fmt.Println("\nBy month:")
+		for i, m := range s.Months {
+			trend := ""
+			if i > 0 {
+				diff := m.Average.Mean() - s.Months[i-1].Average.Mean()
+				switch {
+				case diff >= time.Minute:
+					trend = fmt.Sprintf(" ↑%s", formatDuration(diff))
+				case diff <= -time.Minute:
+					trend = fmt.Sprintf(" ↓%s", formatDuration(-diff))
+				}
+			}
+			fmt.Printf("  %-9s %3d lookups  avg %s%-6s %.1fh on transit\n",
+				m.Month.Format("Jan 2006"), m.Trips, formatDuration(m.Average.Mean()), trend, m.Transit.Hours())
+		}
+
+		tracked, err := trips.Load()
+		if err != nil || len(tracked) == 0 {
+			return
+		}
+		fmt.Println("\nPlanned vs actual (tracked with 'commute start'/'commute arrived'):")
+		for _, g := range trips.NewReliability(tracked, loc).Groups() {
+			fmt.Printf("  %-30s %-8s %2d trips  %s planned, %s actual (%s)\n",
+				truncate(g.Lines, 30), g.Period, g.Trips, formatDuration(g.Planned), formatSlack(g.Actual), signedDuration(g.Actual-g.Planned))
+		}
+	},
+}
+
+func init() {
+	statsCmd.Flags().StringVar(&statsTo, "to", "", "Only trips to home, work or other")
+	statsCmd.Flags().IntVar(&statsMonths, "months", 0, "Only the last N months (default all)")
+	rootCmd.AddCommand(statsCmd)
+}
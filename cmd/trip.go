@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"seattle-commute-cli/config"
+	"seattle-commute-cli/trips"
+)
+
+var startCancel bool
+
+var startCmd = &cobra.Command{
+	Use:   "start [route number]",
+	Short: "Start tracking a trip on one of the routes just shown",
+	Long:  "Pair one of the routes from your last lookup (default the first) with the time you set off. Run 'commute arrived' when you get there to record how long it really took.",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if startCancel {
+			if err := trips.Cancel(); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println("✅ Trip cancelled")
+			return
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		loc := loadRegion(cfg).Location()
+
+		shown, err := trips.Shown()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(shown) == 0 {
+			fmt.Println("❌ No routes to start. Look up routes with 'commute' first.")
+			os.Exit(1)
+		}
+
+		n := 1
+		if len(args) == 1 {
+			n, err = strconv.Atoi(args[0])
+			if err != nil || n < 1 || n > len(shown) {
+				fmt.Printf("❌ Pick a route from 1 to %d\n", len(shown))
+				os.Exit(1)
+			}
+		}
+
+		if picked := shown[n-1]; time.Since(picked.Depart) > trips.StaleAfter {
+			fmt.Printf("❌ Route %d left at %s; look up routes again before starting a trip\n", n, picked.Depart.In(loc).Format("3:04 PM"))
+			os.Exit(1)
+		}
+
+		if active, _ := trips.Active(); active != nil {
+			fmt.Printf("⚠️  Replacing the trip started at %s\n", active.StartedAt.In(loc).Format("3:04 PM"))
+		}
+
+		trip, err := trips.Start(shown[n-1], time.Now())
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		lines := strings.Join(trip.Lines, " → ")
+		if lines == "" {
+			lines = "walk"
+		}
+		fmt.Printf("🚦 Started: %s to %s\n", lines, trip.To)
+		fmt.Printf("   Planned arrival: %s (%s)\n", trip.Arrive.In(loc).Format("3:04 PM"), formatDuration(trip.Duration))
+		fmt.Println("💡 Run 'commute arrived' when you get there")
+	},
+}
+
+var arrivedCmd = &cobra.Command{
+	Use:   "arrived",
+	Short: "Finish the trip in progress and record how long it took",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		loc := loadRegion(cfg).Location()
+
+		trip, err := trips.Arrive(time.Now())
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Arrived at %s after %s (planned %s, %s)\n",
+			trip.ArrivedAt.In(loc).Format("3:04 PM"), formatSlack(trip.Actual()), formatDuration(trip.Duration), signedDuration(trip.Actual()-trip.Duration))
+
+		all, err := trips.Load()
+		if err == nil {
+			fmt.Printf("📈 %d trips recorded; ETAs adjust once a kind of trip has %d\n", len(all), trips.MinSamples)
+		}
+	},
+}
+
+func init() {
+	startCmd.Flags().BoolVar(&startCancel, "cancel", false, "Drop the trip in progress without recording it")
+	rootCmd.AddCommand(startCmd)
+	rootCmd.AddCommand(arrivedCmd)
+}
@@ -0,0 +1,13 @@
+package config
+
+// AccessibilityConfig turns on an accessibility profile for every query.
+type AccessibilityConfig struct {
+	// Profile is wheelchair, no-stairs or limited-walking.
+	Profile string `json:"profile,omitempty"`
+	// MaxStepWalkMinutes overrides the profile's cap on each walking step.
+	MaxStepWalkMinutes float64 `json:"max_step_walk_minutes,omitempty"`
+	// ElevatorFeeds are alert feeds (same formats as alerts.feeds) listing
+	// stations with elevator outages. ACCESSIBILITY_ISSUE alerts in the
+	// regular alert feeds count too.
+	ElevatorFeeds []string `json:"elevator_feeds,omitempty"`
+}
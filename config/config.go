@@ -2,14 +2,184 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+
+	"seattle-commute-cli/geo"
 )
 
 type Config struct {
-	HomeAddress   string `json:"home_address"`
-	WorkAddress   string `json:"work_address,omitempty"`
-	GoogleAPIKey  string `json:"google_api_key"`
+	HomeAddress  string            `json:"home_address"`
+	WorkAddress  string            `json:"work_address,omitempty"`
+	GoogleAPIKey string            `json:"google_api_key"`
+	Home         *Place            `json:"home,omitempty"`
+	Work         *Place            `json:"work,omitempty"`
+	Places       map[string]*Place `json:"places,omitempty"`
+	Region       string            `json:"region,omitempty"`
+	// SnapRadiusMeters is how close a detected location has to be to a
+	// saved place to be treated as that place. Zero uses the default.
+	SnapRadiusMeters float64        `json:"snap_radius_meters,omitempty"`
+	Location         LocationConfig `json:"location,omitzero"`
+	// AutoDirection makes a bare 'commute' work out home vs work itself.
+	AutoDirection bool           `json:"auto_direction,omitempty"`
+	Schedule      []ScheduleRule `json:"schedule,omitempty"`
+	Walking       WalkingConfig  `json:"walking,omitzero"`
+	Costs         CostConfig     `json:"costs,omitzero"`
+	Hubs          []Hub          `json:"hubs,omitempty"`
+	// UseHubs adds bike-and-ride/park-and-ride options to every query.
+	UseHubs     bool             `json:"use_hubs,omitempty"`
+	Score       ScoreConfig      `json:"score,omitzero"`
+	Preferences RoutePreferences `json:"preferences,omitzero"`
+	Ranking     RankingConfig    `json:"ranking,omitzero"`
+	Transfers   TransferConfig   `json:"transfers,omitzero"`
+	Fare        FareConfig       `json:"fare,omitzero"`
+	Alerts      AlertsConfig     `json:"alerts,omitzero"`
+	// Accessibility filters out routes that need stairs, bikes or long walks.
+	Accessibility AccessibilityConfig `json:"accessibility,omitzero"`
+	// DisableHistory stops routes being logged for 'commute stats'.
+	DisableHistory bool `json:"disable_history,omitempty"`
+}
+
+// AlertsConfig lists service alert feeds to check routes against.
+type AlertsConfig struct {
+	// Feeds are URLs or local files with GTFS-realtime alerts as JSON, or
+	// the simple {"alerts": [...]} format.
+	Feeds []string `json:"feeds,omitempty"`
+	// RouteNames and StopNames map feed IDs to the line and stop names
+	// Google uses, e.g. {"100240": "545"}.
+	RouteNames map[string]string `json:"route_names,omitempty"`
+	StopNames  map[string]string `json:"stop_names,omitempty"`
+}
+
+// FareConfig picks the fare table and rider category used to price routes.
+type FareConfig struct {
+	// Table is a fare table name or path; empty uses the region's.
+	Table string `json:"table,omitempty"`
+	// Category is the rider category, e.g. adult, lift, youth or senior.
+	Category string `json:"category,omitempty"`
+	// DaysPerWeek is how often you commute, for monthly projections
+	// (default 5).
+	DaysPerWeek float64 `json:"days_per_week,omitempty"`
+	// MonthlyPass is the price of a pass to compare the projection with.
+	MonthlyPass float64 `json:"monthly_pass,omitempty"`
+}
+
+// TransferConfig sets how much slack a connection needs before it's flagged
+// as tight.
+type TransferConfig struct {
+	// MinMinutes is keyed by "from-to" vehicle mode, e.g. "rail-bus", or
+	// "default".
+	MinMinutes map[string]float64 `json:"min_minutes,omitempty"`
+	// MeanDelayMinutes is how late each mode ("bus", "rail", "tram",
+	// "ferry") typically runs, used to estimate the chance of making a
+	// connection.
+	MeanDelayMinutes map[string]float64 `json:"mean_delay_minutes,omitempty"`
+}
+
+// RankingConfig picks how routes are ordered.
+type RankingConfig struct {
+	// Sort is departure, arrival, duration, transfers or score. Empty ranks
+	// by preferences when there are any, otherwise by departure.
+	Sort string `json:"sort,omitempty"`
+	// Weights override the score weights, in minutes of later arrival:
+	// duration and walking and wait (per minute), transfers (each) and
+	// reliability.
+	Weights map[string]float64 `json:"weights,omitempty"`
+}
+
+// RoutePreferences are passed to Google as hints and then enforced on the
+// routes that come back.
+type RoutePreferences struct {
+	// Modes limits the vehicles ridden: "bus", "rail", "tram", "ferry".
+	Modes          []string `json:"modes,omitempty"`
+	FewerTransfers bool     `json:"fewer_transfers,omitempty"`
+	LessWalking    bool     `json:"less_walking,omitempty"`
+	// Lines avoids or prefers particular lines, e.g. "Bus 8" or "1 Line".
+	Lines []LineRule `json:"lines,omitempty"`
+}
+
+// Hub is a transit station you can bike or drive to, e.g. a station with
+// bike lockers or a park-and-ride lot.
+type Hub struct {
+	Name string `json:"name"`
+	Place
+	// Modes is how you get to or from the hub: "bicycling" and/or "driving".
+	Modes []string `json:"modes"`
+	// TransferMinutes covers locking up or parking (default 5).
+	TransferMinutes float64 `json:"transfer_minutes,omitempty"`
+}
+
+// CostConfig overrides the defaults used to estimate trip costs.
+type CostConfig struct {
+	DrivingPerMile float64 `json:"driving_per_mile,omitempty"`
+	Parking        float64 `json:"parking,omitempty"`
+	TransitFare    float64 `json:"transit_fare,omitempty"`
+}
+
+// LocationConfig controls how the current location is detected.
+type LocationConfig struct {
+	// Providers is the order to try location providers in; empty uses the
+	// built-in order.
+	Providers []string `json:"providers,omitempty"`
+	Disabled  []string `json:"disabled,omitempty"`
+	// MinConfidence is the lowest confidence (none, low, medium, high) we'll
+	// route from. Fixes below it are refused; low ones get a warning.
+	MinConfidence string `json:"min_confidence,omitempty"`
+	// NetworkCommand is run through the shell and its output used as an
+	// extra network fingerprint.
+	NetworkCommand string `json:"network_command,omitempty"`
+}
+
+const DefaultSnapRadiusMeters = 150
+
+// Place is an address together with the geocoding result we got for it, so
+// routing requests can use the exact coordinates/place ID instead of asking
+// Google to re-geocode the free-text address every run.
+type Place struct {
+	Address          string  `json:"address"`
+	FormattedAddress string  `json:"formatted_address,omitempty"`
+	PlaceID          string  `json:"place_id,omitempty"`
+	Lat              float64 `json:"lat,omitempty"`
+	Lng              float64 `json:"lng,omitempty"`
+	// Networks are fingerprints learned with 'commute places learn'.
+	Networks *Networks `json:"networks,omitempty"`
+}
+
+type Networks struct {
+	SSIDs       []string `json:"ssids,omitempty"`
+	GatewayMACs []string `json:"gateway_macs,omitempty"`
+	Custom      []string `json:"custom,omitempty"`
+}
+
+func (p *Place) HasCoordinates() bool {
+	return p.Lat != 0 || p.Lng != 0
+}
+
+func (p *Place) IsGeocoded() bool {
+	return p.PlaceID != "" || p.HasCoordinates()
+}
+
+// Query returns the value to hand to the Directions/Distance APIs: the place
+// ID when we have one, then coordinates, then the raw address.
+func (p *Place) Query() string {
+	if p.PlaceID != "" {
+		return "place_id:" + p.PlaceID
+	}
+	if p.HasCoordinates() {
+		return fmt.Sprintf("%f,%f", p.Lat, p.Lng)
+	}
+	return p.Address
+}
+
+// Label is the human-readable form of the place for output.
+func (p *Place) Label() string {
+	if p.FormattedAddress != "" {
+		return p.FormattedAddress
+	}
+	return p.Address
 }
 
 func GetConfigPath() string {
@@ -56,4 +226,118 @@ func (c *Config) Save() error {
 
 func (c *Config) IsValid() bool {
 	return c.HomeAddress != "" && c.GoogleAPIKey != ""
-}
\ No newline at end of file
+}
+
+// HomePlace returns the geocoded home place, or a bare place built from
+// HomeAddress if the stored geocode is missing or stale.
+func (c *Config) HomePlace() *Place {
+	if c.Home != nil && (c.Home.Address == c.HomeAddress || c.Home.FormattedAddress == c.HomeAddress) {
+		return c.Home
+	}
+	return &Place{Address: c.HomeAddress}
+}
+
+func (c *Config) WorkPlace() *Place {
+	if c.WorkAddress == "" {
+		return nil
+	}
+	if c.Work != nil && (c.Work.Address == c.WorkAddress || c.Work.FormattedAddress == c.WorkAddress) {
+		return c.Work
+	}
+	return &Place{Address: c.WorkAddress}
+}
+
+func (c *Config) SetHome(p *Place) {
+	c.Home = p
+	c.HomeAddress = p.Label()
+}
+
+func (c *Config) SetWork(p *Place) {
+	c.Work = p
+	c.WorkAddress = p.Label()
+}
+
+// LookupPlace resolves "home", "work" or the name of a saved place.
+func (c *Config) LookupPlace(name string) (*Place, bool) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	switch key {
+	case "home":
+		if c.HomeAddress == "" {
+			return nil, false
+		}
+		return c.HomePlace(), true
+	case "work":
+		p := c.WorkPlace()
+		return p, p != nil
+	}
+	p, ok := c.Places[key]
+	return p, ok
+}
+
+func (c *Config) SetPlace(name string, p *Place) {
+	if c.Places == nil {
+		c.Places = make(map[string]*Place)
+	}
+	c.Places[strings.ToLower(strings.TrimSpace(name))] = p
+}
+
+func (c *Config) RemovePlace(name string) bool {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if _, ok := c.Places[key]; !ok {
+		return false
+	}
+	delete(c.Places, key)
+	return true
+}
+
+func (c *Config) SnapRadius() float64 {
+	if c.SnapRadiusMeters > 0 {
+		return c.SnapRadiusMeters
+	}
+	return DefaultSnapRadiusMeters
+}
+
+// NearestPlace returns the saved place (including home and work) closest to
+// pt, provided it has coordinates and lies within radius meters.
+func (c *Config) NearestPlace(pt geo.Point, radius float64) (string, *Place, bool) {
+	candidates := c.AllPlaces()
+
+	bestName := ""
+	var best *Place
+	bestDist := radius
+	for name, p := range candidates {
+		if !p.HasCoordinates() {
+			continue
+		}
+		d := geo.Distance(pt, geo.Point{Lat: p.Lat, Lng: p.Lng})
+		if d <= bestDist {
+			bestName, best, bestDist = name, p, d
+		}
+	}
+	return bestName, best, best != nil
+}
+
+// AllPlaces returns home, work and the saved places keyed by name.
+func (c *Config) AllPlaces() map[string]*Place {
+	places := map[string]*Place{}
+	if c.HomeAddress != "" {
+		places["home"] = c.HomePlace()
+	}
+	if work := c.WorkPlace(); work != nil {
+		places["work"] = work
+	}
+	for name, p := range c.Places {
+		places[name] = p
+	}
+	return places
+}
+
+// PlaceNames returns the saved place names in sorted order.
+func (c *Config) PlaceNames() []string {
+	names := make([]string, 0, len(c.Places))
+	for name := range c.Places {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LineRule avoids or prefers a transit line, optionally only on some days or
+// between some times, e.g. {"line": "545", "action": "avoid", "start":
+// "18:00"}. Days, Start and End use the same syntax as schedule rules.
+type LineRule struct {
+	Line   string `json:"line"`
+	Action string `json:"action"`
+	Days   string `json:"days,omitempty"`
+	Start  string `json:"start,omitempty"`
+	End    string `json:"end,omitempty"`
+}
+
+// Validate checks the action and the time window.
+func (r LineRule) Validate() error {
+	if strings.TrimSpace(r.Line) == "" {
+		return fmt.Errorf("line rule without a line")
+	}
+	switch strings.ToLower(r.Action) {
+	case "avoid", "prefer":
+	default:
+		return fmt.Errorf("line rule for %q: action must be avoid or prefer, got %q", r.Line, r.Action)
+	}
+	if _, err := r.Active(time.Now()); err != nil {
+		return fmt.Errorf("line rule for %q: %v", r.Line, err)
+	}
+	return nil
+}
+
+// Avoid reports whether the rule avoids its line rather than preferring it.
+func (r LineRule) Avoid() bool {
+	return strings.EqualFold(r.Action, "avoid")
+}
+
+// Active reports whether the rule applies to boarding at t.
+func (r LineRule) Active(t time.Time) (bool, error) {
+	return ScheduleRule{Days: r.Days, Start: r.Start, End: r.End}.Matches(t)
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ScheduleRule says where you're usually heading on some days between two
+// times of day, e.g. {"days": "mon-fri", "start": "05:00", "end": "11:00",
+// "to": "work"}.
+type ScheduleRule struct {
+	Days  string `json:"days"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+	To    string `json:"to"`
+}
+
+// DefaultSchedule is used when auto direction is on but no schedule is
+// configured: weekday mornings to work, the rest of the weekday home.
+var DefaultSchedule = []ScheduleRule{
+	{Days: "mon-fri", Start: "04:00", End: "11:30", To: "work"},
+	{Days: "mon-fri", Start: "11:30", End: "23:59", To: "home"},
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Matches reports whether t falls inside the rule.
+func (r ScheduleRule) Matches(t time.Time) (bool, error) {
+	days, err := parseDays(r.Days)
+	if err != nil {
+		return false, err
+	}
+	if !days[t.Weekday()] {
+		return false, nil
+	}
+
+	start, err := parseClock(r.Start, 0)
+	if err != nil {
+		return false, err
+	}
+	end, err := parseClock(r.End, 24*60)
+	if err != nil {
+		return false, err
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	if start <= end {
+		return minute >= start && minute <= end, nil
+	}
+	// Wraps past midnight
+	return minute >= start || minute <= end, nil
+}
+
+func (r ScheduleRule) String() string {
+	return fmt.Sprintf("%s %s-%s", r.Days, r.Start, r.End)
+}
+
+// MatchSchedule returns the first rule matching t.
+func MatchSchedule(rules []ScheduleRule, t time.Time) (*ScheduleRule, error) {
+	for i := range rules {
+		ok, err := rules[i].Matches(t)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule rule %q: %v", rules[i], err)
+		}
+		if ok {
+			return &rules[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// parseDays accepts "daily", "weekdays", "weekends", day ranges like
+// "mon-fri" and comma lists like "sat,sun".
+func parseDays(s string) (map[time.Weekday]bool, error) {
+	days := make(map[time.Weekday]bool)
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "", "daily":
+		s = "sun-sat"
+	case "weekdays":
+		s = "mon-fri"
+	case "weekends":
+		s = "sat,sun"
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if from, to, ok := strings.Cut(part, "-"); ok {
+			start, ok1 := weekdays[from]
+			end, ok2 := weekdays[to]
+			if !ok1 || !ok2 {
+				return nil, fmt.Errorf("unknown day range %q", part)
+			}
+			for d := start; ; d = (d + 1) % 7 {
+				days[d] = true
+				if d == end {
+					break
+				}
+			}
+			continue
+		}
+		d, ok := weekdays[part]
+		if !ok {
+			return nil, fmt.Errorf("unknown day %q", part)
+		}
+		days[d] = true
+	}
+	return days, nil
+}
+
+// parseClock turns "HH:MM" into minutes after midnight.
+func parseClock(s string, def int) (int, error) {
+	if s == "" {
+		return def, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (use HH:MM)", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// NextTime returns the next time at or after now that falls on one of days
+// (same syntax as schedule rules) at the HH:MM clock time.
+func NextTime(days, clock string, now time.Time) (time.Time, error) {
+	allowed, err := parseDays(days)
+	if err != nil {
+		return time.Time{}, err
+	}
+	minute, err := parseClock(clock, 0)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for i := 0; i < 8; i++ {
+		t := day.AddDate(0, 0, i).Add(time.Duration(minute) * time.Minute)
+		if allowed[t.Weekday()] && !t.Before(now) {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("no matching day in %q", days)
+}
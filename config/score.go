@@ -0,0 +1,27 @@
+package config
+
+// ScoreConfig tunes 'commute score'.
+type ScoreConfig struct {
+	// Targets are saved place names to score against (default: work and
+	// every saved place).
+	Targets []string    `json:"targets,omitempty"`
+	Times   []ScoreTime `json:"times,omitempty"`
+	// Weights override the default weight of each component: median,
+	// worst, transfers, walk and frequency.
+	Weights map[string]float64 `json:"weights,omitempty"`
+}
+
+// ScoreTime is a moment a candidate home is evaluated at. Direction "to"
+// travels from the candidate to the target, "from" comes back.
+type ScoreTime struct {
+	Label     string `json:"label"`
+	Days      string `json:"days"`
+	At        string `json:"at"`
+	Direction string `json:"direction,omitempty"`
+}
+
+var DefaultScoreTimes = []ScoreTime{
+	{Label: "Weekday 8:00 AM", Days: "weekdays", At: "08:00", Direction: "to"},
+	{Label: "Weekday 5:30 PM", Days: "weekdays", At: "17:30", Direction: "from"},
+	{Label: "Saturday noon", Days: "sat", At: "12:00", Direction: "to"},
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultMaxWalk = 15 * time.Minute
+	DefaultArrived = 2 * time.Minute
+)
+
+// WalkingConfig controls when we tell you to just walk instead of showing
+// transit.
+type WalkingConfig struct {
+	// MaxMinutes is the longest walk that replaces transit (default 15).
+	MaxMinutes float64 `json:"max_minutes,omitempty"`
+	// ArrivedMinutes is how close counts as already being there (default 2).
+	ArrivedMinutes float64 `json:"arrived_minutes,omitempty"`
+	// AlwaysShowTransit shows transit routes even when walking is an option.
+	AlwaysShowTransit bool `json:"always_show_transit,omitempty"`
+	// Profile scales MaxMinutes down, e.g. "rain" or "mobility".
+	Profile string `json:"profile,omitempty"`
+	// RainMonths turns on the rain profile automatically in these months
+	// (1-12) when no other profile is set.
+	RainMonths []int `json:"rain_months,omitempty"`
+	// Profiles overrides or adds profile factors.
+	Profiles map[string]float64 `json:"profiles,omitempty"`
+}
+
+// BuiltinWalkProfiles are multipliers applied to the max walk.
+var BuiltinWalkProfiles = map[string]float64{
+	"rain":     0.6,
+	"mobility": 0.5,
+}
+
+// WalkThresholds resolves the max walk and "already there" thresholds for
+// the given month. profile overrides the configured profile; "none" turns
+// profiles off.
+func (w WalkingConfig) WalkThresholds(month time.Month, profile string) (maxWalk, arrived time.Duration, activeProfile string, err error) {
+	maxWalk = DefaultMaxWalk
+	if w.MaxMinutes > 0 {
+		maxWalk = time.Duration(w.MaxMinutes * float64(time.Minute))
+	}
+	arrived = DefaultArrived
+	if w.ArrivedMinutes > 0 {
+		arrived = time.Duration(w.ArrivedMinutes * float64(time.Minute))
+	}
+
+	if profile == "" {
+		profile = w.Profile
+	}
+	if profile == "" {
+		for _, m := range w.RainMonths {
+			if time.Month(m) == month {
+				profile = "rain"
+				break
+			}
+		}
+	}
+	profile = strings.ToLower(profile)
+	if profile == "" || profile == "none" {
+		return maxWalk, arrived, "", nil
+	}
+
+	factor, ok := w.Profiles[profile]
+	if !ok {
+		factor, ok = BuiltinWalkProfiles[profile]
+	}
+	if !ok {
+		return 0, 0, "", fmt.Errorf("unknown walking profile %q (available: %s)", profile, strings.Join(w.profileNames(), ", "))
+	}
+
+	maxWalk = time.Duration(float64(maxWalk) * factor)
+	if arrived > maxWalk {
+		arrived = maxWalk
+	}
+	return maxWalk, arrived, profile, nil
+}
+
+func (w WalkingConfig) profileNames() []string {
+	seen := map[string]bool{}
+	for name := range BuiltinWalkProfiles {
+		seen[name] = true
+	}
+	for name := range w.Profiles {
+		seen[name] = true
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
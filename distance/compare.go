@@ -0,0 +1,88 @@
+package distance
+
+import (
+	"sync"
+	"time"
+
+	"googlemaps.github.io/maps"
+)
+
+const metersPerMile = 1609.344
+
+// CostModel turns a trip into an estimated dollar cost. Walking and biking
+// are free.
+type CostModel struct {
+	DrivingPerMile float64
+	// DrivingFixed covers parking/tolls per trip.
+	DrivingFixed float64
+	TransitFare  float64
+}
+
+// DefaultCostModel uses a rough per-mile operating cost and the adult
+// King County Metro fare.
+var DefaultCostModel = CostModel{
+	DrivingPerMile: 0.30,
+	TransitFare:    2.75,
+}
+
+type ModeResult struct {
+	Mode     maps.Mode
+	Duration time.Duration
+	Distance string
+	Meters   int
+	Cost     float64
+	Err      error
+}
+
+// ComparisonModes are the modes CompareModes queries, in display order.
+var ComparisonModes = []maps.Mode{
+	maps.TravelModeWalking,
+	maps.TravelModeBicycling,
+	maps.TravelModeTransit,
+	maps.TravelModeDriving,
+}
+
+// CompareModes queries every mode in ComparisonModes concurrently. A mode
+// that fails carries its error in the result rather than failing the whole
+// comparison.
+func (dc *DistanceChecker) CompareModes(origin, destination string, costs CostModel) []ModeResult {
+	results := make([]ModeResult, len(ComparisonModes))
+
+	var wg sync.WaitGroup
+	for i, mode := range ComparisonModes {
+		wg.Add(1)
+		go func(i int, mode maps.Mode) {
+			defer wg.Done()
+			results[i] = dc.queryMode(origin, destination, mode, costs)
+		}(i, mode)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func (dc *DistanceChecker) queryMode(origin, destination string, mode maps.Mode, costs CostModel) ModeResult {
+	result := ModeResult{Mode: mode}
+
+	route, leg, err := dc.directions(origin, destination, mode)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.Duration = legDuration(leg)
+	result.Distance = leg.Distance.HumanReadable
+	result.Meters = leg.Distance.Meters
+
+	switch mode {
+	case maps.TravelModeDriving:
+		result.Cost = costs.DrivingFixed + costs.DrivingPerMile*float64(leg.Distance.Meters)/metersPerMile
+	case maps.TravelModeTransit:
+		result.Cost = costs.TransitFare
+		if route.Fare != nil {
+			result.Cost = route.Fare.Value
+		}
+	}
+
+	return result
+}
@@ -21,36 +21,61 @@ func NewDistanceChecker(apiKey string) (*DistanceChecker, error) {
 }
 
 func (dc *DistanceChecker) GetWalkingDistance(origin, destination string) (time.Duration, string, error) {
+	return dc.GetTravelTime(origin, destination, maps.TravelModeWalking)
+}
+
+// GetTravelTime returns the duration and distance for a non-transit mode.
+// Driving uses current traffic.
+func (dc *DistanceChecker) GetTravelTime(origin, destination string, mode maps.Mode) (time.Duration, string, error) {
+	_, leg, err := dc.directions(origin, destination, mode)
+	if err != nil {
+		return 0, "", err
+	}
+	return legDuration(leg), leg.Distance.HumanReadable, nil
+}
+
+// directions fetches the first route for mode, leaving now, and its first
+// leg.
+func (dc *DistanceChecker) directions(origin, destination string, mode maps.Mode) (maps.Route, *maps.Leg, error) {
 	ctx := context.Background()
 
 	req := &maps.DirectionsRequest{
 		Origin:      origin,
 		Destination: destination,
-		Mode:        maps.TravelModeWalking,
+		Mode:        mode,
 		Units:       maps.UnitsImperial,
 	}
+	// Needed for transit schedules and driving traffic
+	if mode == maps.TravelModeTransit || mode == maps.TravelModeDriving {
+		req.DepartureTime = "now"
+	}
 
 	resp, _, err := dc.client.Directions(ctx, req)
 	if err != nil {
-		return 0, "", fmt.Errorf("failed to get walking directions: %v", err)
+		return maps.Route{}, nil, fmt.Errorf("failed to get %s directions: %v", mode, err)
 	}
 
 	if len(resp) == 0 || len(resp[0].Legs) == 0 {
-		return 0, "", fmt.Errorf("no walking route found")
+		return maps.Route{}, nil, fmt.Errorf("no %s route found", mode)
 	}
+	return resp[0], resp[0].Legs[0], nil
+}
 
-	leg := resp[0].Legs[0]
-	return leg.Duration, leg.Distance.HumanReadable, nil
+// legDuration prefers the time in current traffic when Google gives one.
+func legDuration(leg *maps.Leg) time.Duration {
+	if leg.DurationInTraffic > 0 {
+		return leg.DurationInTraffic
+	}
+	return leg.Duration
 }
 
-func (dc *DistanceChecker) IsWithinWalkingDistance(origin, destination string) (bool, time.Duration, string, error) {
+func (dc *DistanceChecker) IsWithinWalkingDistance(origin, destination string, maxWalk time.Duration) (bool, time.Duration, string, error) {
 	walkTime, walkDistance, err := dc.GetWalkingDistance(origin, destination)
 	if err != nil {
 		return false, 0, "", err
 	}
 
-	// Consider "walking distance" as 15 minutes or less
-	isWalkable := walkTime <= 15*time.Minute
+	isWalkable := walkTime <= maxWalk
 
 	return isWalkable, walkTime, walkDistance, nil
 }
\ No newline at end of file
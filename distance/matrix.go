@@ -0,0 +1,107 @@
+package distance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"googlemaps.github.io/maps"
+)
+
+// Distance Matrix API limits per request.
+const (
+	matrixMaxPerSide  = 25
+	matrixMaxElements = 100
+)
+
+type MatrixCell struct {
+	Duration time.Duration
+	Distance string
+	Meters   int
+	// Status is "OK" or the API's per-element status, e.g. "ZERO_RESULTS".
+	Status string
+}
+
+func (c MatrixCell) OK() bool {
+	return c.Status == "OK"
+}
+
+// Matrix holds travel times for every origin × destination pair;
+// Cells[i][j] is Origins[i] to Destinations[j].
+type Matrix struct {
+	Origins      []string
+	Destinations []string
+	Cells        [][]MatrixCell
+}
+
+// GetMatrix evaluates every origin against every destination with the
+// Distance Matrix API, splitting into as few requests as the API's limits
+// allow. A zero departAt means now.
+func (dc *DistanceChecker) GetMatrix(origins, destinations []string, mode maps.Mode, departAt time.Time) (*Matrix, error) {
+	if len(origins) == 0 || len(destinations) == 0 {
+		return nil, fmt.Errorf("need at least one origin and one destination")
+	}
+
+	m := &Matrix{
+		Origins:      origins,
+		Destinations: destinations,
+		Cells:        make([][]MatrixCell, len(origins)),
+	}
+	for i := range m.Cells {
+		m.Cells[i] = make([]MatrixCell, len(destinations))
+	}
+
+	destBatch := min(len(destinations), matrixMaxPerSide)
+	originBatch := min(matrixMaxPerSide, matrixMaxElements/destBatch)
+
+	for oi := 0; oi < len(origins); oi += originBatch {
+		oEnd := min(oi+originBatch, len(origins))
+		for di := 0; di < len(destinations); di += destBatch {
+			dEnd := min(di+destBatch, len(destinations))
+			if err := dc.fillMatrix(m, oi, oEnd, di, dEnd, mode, departAt); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return m, nil
+}
+
+func (dc *DistanceChecker) fillMatrix(m *Matrix, oStart, oEnd, dStart, dEnd int, mode maps.Mode, departAt time.Time) error {
+	req := &maps.DistanceMatrixRequest{
+		Origins:      m.Origins[oStart:oEnd],
+		Destinations: m.Destinations[dStart:dEnd],
+		Mode:         mode,
+		Units:        maps.UnitsImperial,
+	}
+	if mode == maps.TravelModeTransit || mode == maps.TravelModeDriving {
+		req.DepartureTime = "now"
+		if !departAt.IsZero() {
+			req.DepartureTime = fmt.Sprintf("%d", departAt.Unix())
+		}
+	}
+
+	resp, err := dc.client.DistanceMatrix(context.Background(), req)
+	if err != nil {
+		return fmt.Errorf("failed to get distance matrix: %v", err)
+	}
+
+	for i, row := range resp.Rows {
+		for j, el := range row.Elements {
+			if oStart+i >= oEnd || dStart+j >= dEnd {
+				continue
+			}
+			cell := MatrixCell{Status: el.Status}
+			if el.Status == "OK" {
+				cell.Duration = el.Duration
+				if el.DurationInTraffic > 0 {
+					cell.Duration = el.DurationInTraffic
+				}
+				cell.Distance = el.Distance.HumanReadable
+				cell.Meters = el.Distance.Meters
+			}
+			m.Cells[oStart+i][dStart+j] = cell
+		}
+	}
+	return nil
+}
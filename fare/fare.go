@@ -0,0 +1,223 @@
+package fare
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"seattle-commute-cli/transit"
+)
+
+//go:embed tables
+var builtinTables embed.FS
+
+const DefaultCategory = "adult"
+
+// Product is a fare charged by an agency, optionally only on some vehicle
+// modes or lines (matched like line rules: "N Line", "545"). Agencies lists
+// every name Google gives the operator; they're matched exactly, ignoring
+// case, so "Metro" doesn't catch every agency with Metro in its name.
+type Product struct {
+	Name     string             `json:"name"`
+	Agencies []string           `json:"agencies"`
+	Modes    []string           `json:"modes,omitempty"`
+	Lines    []string           `json:"lines,omitempty"`
+	Fares    map[string]float64 `json:"fares"`
+	// NoTransfer products neither use nor give transfer credit.
+	NoTransfer bool `json:"no_transfer,omitempty"`
+}
+
+// Table is a fare system: its products and transfer rule. Tables are data
+// files so fares can be updated without a new build.
+type Table struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	AsOf        string `json:"as_of"`
+	Currency    string `json:"currency"`
+	// TransferWindowMinutes is how long the credit from the first fare
+	// lasts; later rides only pay the difference if they cost more.
+	TransferWindowMinutes float64           `json:"transfer_window_minutes"`
+	Categories            map[string]string `json:"categories"`
+	Products              []Product         `json:"products"`
+}
+
+// TablesDir is where custom fare tables are looked up by name.
+func TablesDir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".seattle-commute", "fares")
+}
+
+// Load resolves a fare table by built-in name, by name of a file in
+// TablesDir, or by path to a JSON file.
+func Load(nameOrPath string) (*Table, error) {
+	lower := strings.ToLower(nameOrPath)
+	if strings.HasSuffix(lower, ".json") {
+		return loadFile(os.ReadFile, nameOrPath)
+	}
+
+	custom := filepath.Join(TablesDir(), lower+".json")
+	if _, err := os.Stat(custom); err == nil {
+		return loadFile(os.ReadFile, custom)
+	}
+
+	t, err := loadFile(builtinTables.ReadFile, "tables/"+lower+".json")
+	if err != nil {
+		return nil, fmt.Errorf("unknown fare table %q (add %s to define it)", nameOrPath, custom)
+	}
+	return t, nil
+}
+
+func loadFile(readFile func(string) ([]byte, error), path string) (*Table, error) {
+	data, err := readFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fare table: %v", err)
+	}
+
+	var t Table
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("invalid fare table %s: %v", path, err)
+	}
+	for _, p := range t.Products {
+		if len(p.Agencies) == 0 {
+			return nil, fmt.Errorf("invalid fare table %s: product %q lists no agencies", path, p.Name)
+		}
+	}
+	if t.DisplayName == "" {
+		t.DisplayName = t.Name
+	}
+	return &t, nil
+}
+
+// CategoryNames lists the rider categories the table has fares for.
+func (t *Table) CategoryNames() []string {
+	names := make([]string, 0, len(t.Categories))
+	for name := range t.Categories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (p Product) matches(step transit.Step) bool {
+	known := false
+	for _, agency := range p.Agencies {
+		if strings.EqualFold(step.Agency, agency) {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return false
+	}
+	if len(p.Modes) > 0 && !contains(p.Modes, step.VehicleMode()) {
+		return false
+	}
+	if len(p.Lines) == 0 {
+		return true
+	}
+	for _, line := range p.Lines {
+		if step.OnLine(line) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Table) product(step transit.Step) (Product, bool) {
+	for _, p := range t.Products {
+		if p.matches(step) {
+			return p, true
+		}
+	}
+	return Product{}, false
+}
+
+// Ride is what one transit step costs.
+type Ride struct {
+	Line    string
+	Product string
+	Fare    float64
+	// Paid is the fare less any transfer credit.
+	Paid float64
+}
+
+type Quote struct {
+	Total    float64
+	Currency string
+	Category string
+	Rides    []Ride
+	// FromGoogle is set when some ride had no product in the table and
+	// the total is Google's fare for the route instead.
+	FromGoogle bool
+}
+
+func (q Quote) String() string {
+	if q.Currency != "" && q.Currency != "USD" {
+		return fmt.Sprintf("%.2f %s", q.Total, q.Currency)
+	}
+	return fmt.Sprintf("$%.2f", q.Total)
+}
+
+// Quote prices a route for a rider category. A fare paid starts a transfer
+// window; rides boarded inside it are free up to the credit and pay the
+// difference if they cost more. If a ride isn't covered by the table, the
+// route's Google fare is used when there is one.
+func (t *Table) Quote(r transit.Route, category string) (Quote, error) {
+	if category == "" {
+		category = DefaultCategory
+	}
+	if _, ok := t.Categories[category]; !ok {
+		return Quote{}, fmt.Errorf("unknown fare category %q (use %s)", category, strings.Join(t.CategoryNames(), ", "))
+	}
+
+	q := Quote{Currency: t.Currency, Category: category}
+	window := time.Duration(t.TransferWindowMinutes * float64(time.Minute))
+	var windowStart time.Time
+	var credit float64
+
+	for _, step := range r.TransitSteps() {
+		p, ok := t.product(step)
+		if !ok {
+			if r.Fare > 0 {
+				return Quote{Total: r.Fare, Currency: r.FareCurrency, Category: category, FromGoogle: true}, nil
+			}
+			return Quote{}, fmt.Errorf("no fare for %s (%s)", step.LineInfo, step.Agency)
+		}
+
+		ride := Ride{Line: step.LineInfo, Product: p.Name, Fare: p.Fares[category]}
+		switch {
+		case p.NoTransfer:
+			ride.Paid = ride.Fare
+		case !windowStart.IsZero() && step.DepartTime.Sub(windowStart) <= window:
+			ride.Paid = max(ride.Fare-credit, 0)
+			credit = max(credit, ride.Fare)
+		default:
+			ride.Paid = ride.Fare
+			windowStart, credit = step.DepartTime, ride.Fare
+		}
+
+		q.Rides = append(q.Rides, ride)
+		q.Total += ride.Paid
+	}
+	return q, nil
+}
+
+// Monthly projects the cost of making a round trip at perTrip each way on
+// daysPerWeek days.
+func Monthly(perTrip, daysPerWeek float64) float64 {
+	return perTrip * 2 * daysPerWeek * 52 / 12
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
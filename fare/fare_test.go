@@ -0,0 +1,92 @@
+package fare
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"seattle-commute-cli/transit"
+)
+
+var start = time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+
+func ride(agency, vehicle, line string, minutes int) transit.Step {
+	return transit.Step{
+		Mode:       "TRANSIT",
+		Agency:     agency,
+		Vehicle:    vehicle,
+		LineInfo:   line,
+		DepartTime: start.Add(time.Duration(minutes) * time.Minute),
+	}
+}
+
+var (
+	metro   = func(m int) transit.Step { return ride("King County Metro", "BUS", "Bus 40", m) }
+	link    = func(m int) transit.Step { return ride("Sound Transit", "METRO_RAIL", "1 Line", m) }
+	sounder = func(m int) transit.Step { return ride("Sound Transit", "HEAVY_RAIL", "S Line", m) }
+	ferry   = func(m int) transit.Step { return ride("Washington State Ferries", "FERRY", "Bainbridge", m) }
+)
+
+func TestQuote(t *testing.T) {
+	table, err := Load("orca")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name      string
+		category  string
+		steps     []transit.Step
+		googleFee float64
+		want      float64
+		paid      []float64
+		google    bool
+		wantErr   bool
+	}{
+		{name: "single ride", steps: []transit.Step{metro(0)}, want: 2.75, paid: []float64{2.75}},
+		{name: "upgrade in window pays the difference", steps: []transit.Step{metro(0), link(30)}, want: 3.00, paid: []float64{2.75, 0.25}},
+		{name: "cheaper ride in window is free", steps: []transit.Step{link(0), metro(119)}, want: 3.00, paid: []float64{3.00, 0}},
+		{name: "upgrade at the window edge", steps: []transit.Step{metro(0), sounder(120)}, want: 5.00, paid: []float64{2.75, 2.25}},
+		{name: "ride after the window pays full fare", steps: []transit.Step{metro(0), metro(121)}, want: 5.50, paid: []float64{2.75, 2.75}},
+		{name: "ride after the window starts a new one", steps: []transit.Step{metro(0), link(130), metro(200)}, want: 5.75, paid: []float64{2.75, 3.00, 0}},
+		{name: "ferry neither uses nor gives credit", steps: []transit.Step{metro(0), ferry(30), metro(90)}, want: 13.00, paid: []float64{2.75, 10.25, 0}},
+		{name: "ferry first gives no credit", steps: []transit.Step{ferry(0), metro(40)}, want: 13.00, paid: []float64{10.25, 2.75}},
+		{name: "rider category", category: "lift", steps: []transit.Step{metro(0), sounder(20)}, want: 1.00, paid: []float64{1.00, 0}},
+		{name: "unknown agency uses Google's fare", steps: []transit.Step{metro(0), ride("Metrolink", "BUS", "Bus 1", 20)}, googleFee: 4.50, want: 4.50, google: true},
+		{name: "unknown agency without Google's fare", steps: []transit.Step{ride("Metrolink", "BUS", "Bus 1", 0)}, wantErr: true},
+		{name: "unknown category", category: "student", steps: []transit.Step{metro(0)}, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := transit.Route{Steps: c.steps, Fare: c.googleFee, FareCurrency: "USD"}
+			q, err := table.Quote(r, c.category)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("got %s, want an error", q)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(q.Total-c.want) > 0.001 {
+				t.Errorf("total %s, want $%.2f", q, c.want)
+			}
+			if q.FromGoogle != c.google {
+				t.Errorf("FromGoogle = %v, want %v", q.FromGoogle, c.google)
+			}
+			if c.google {
+				return
+			}
+			if len(q.Rides) != len(c.paid) {
+				t.Fatalf("%d rides, want %d", len(q.Rides), len(c.paid))
+			}
+			for i, ride := range q.Rides {
+				if math.Abs(ride.Paid-c.paid[i]) > 0.001 {
+					t.Errorf("ride %d (%s) paid $%.2f, want $%.2f", i+1, ride.Product, ride.Paid, c.paid[i])
+				}
+			}
+		})
+	}
+}
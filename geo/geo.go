@@ -0,0 +1,188 @@
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+// Polygon is a list of linear rings; the first is the outer boundary and
+// any others are holes.
+type Polygon [][]Point
+
+func (p Polygon) Contains(pt Point) bool {
+	if len(p) == 0 || !ringContains(p[0], pt) {
+		return false
+	}
+	for _, hole := range p[1:] {
+		if ringContains(hole, pt) {
+			return false
+		}
+	}
+	return true
+}
+
+// ringContains is the standard even-odd ray casting test.
+func ringContains(ring []Point, pt Point) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Lat > pt.Lat) != (b.Lat > pt.Lat) &&
+			pt.Lng < (b.Lng-a.Lng)*(pt.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lng {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Feature is a named area made of one or more polygons.
+type Feature struct {
+	Name     string
+	Polygons []Polygon
+}
+
+func (f Feature) Contains(pt Point) bool {
+	for _, poly := range f.Polygons {
+		if poly.Contains(pt) {
+			return true
+		}
+	}
+	return false
+}
+
+type geoJSON struct {
+	Type       string          `json:"type"`
+	Features   []geoJSON       `json:"features"`
+	Geometry   *geoJSON        `json:"geometry"`
+	Properties map[string]any  `json:"properties"`
+	Coords     json.RawMessage `json:"coordinates"`
+	Geometries []geoJSON       `json:"geometries"`
+}
+
+// ParseGeoJSON reads a FeatureCollection, Feature or bare Polygon/MultiPolygon
+// geometry and returns its polygonal features. Non-polygon geometries are
+// ignored.
+func ParseGeoJSON(data []byte) ([]Feature, error) {
+	var doc geoJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid GeoJSON: %v", err)
+	}
+
+	var features []Feature
+	if err := collectFeatures(&doc, "", &features); err != nil {
+		return nil, err
+	}
+	if len(features) == 0 {
+		return nil, fmt.Errorf("GeoJSON contains no polygons")
+	}
+	return features, nil
+}
+
+func collectFeatures(doc *geoJSON, name string, out *[]Feature) error {
+	switch doc.Type {
+	case "FeatureCollection":
+		for i := range doc.Features {
+			if err := collectFeatures(&doc.Features[i], "", out); err != nil {
+				return err
+			}
+		}
+	case "Feature":
+		if n, ok := doc.Properties["name"].(string); ok {
+			name = n
+		}
+		if doc.Geometry != nil {
+			return collectFeatures(doc.Geometry, name, out)
+		}
+	case "GeometryCollection":
+		for i := range doc.Geometries {
+			if err := collectFeatures(&doc.Geometries[i], name, out); err != nil {
+				return err
+			}
+		}
+	case "Polygon":
+		var coords [][][]float64
+		if err := json.Unmarshal(doc.Coords, &coords); err != nil {
+			return fmt.Errorf("invalid Polygon coordinates: %v", err)
+		}
+		*out = append(*out, Feature{Name: name, Polygons: []Polygon{toPolygon(coords)}})
+	case "MultiPolygon":
+		var coords [][][][]float64
+		if err := json.Unmarshal(doc.Coords, &coords); err != nil {
+			return fmt.Errorf("invalid MultiPolygon coordinates: %v", err)
+		}
+		f := Feature{Name: name}
+		for _, poly := range coords {
+			f.Polygons = append(f.Polygons, toPolygon(poly))
+		}
+		*out = append(*out, f)
+	}
+	return nil
+}
+
+// GeoJSON positions are [lng, lat].
+func toPolygon(coords [][][]float64) Polygon {
+	poly := make(Polygon, 0, len(coords))
+	for _, ring := range coords {
+		pts := make([]Point, 0, len(ring))
+		for _, pos := range ring {
+			if len(pos) >= 2 {
+				pts = append(pts, Point{Lat: pos[1], Lng: pos[0]})
+			}
+		}
+		poly = append(poly, pts)
+	}
+	return poly
+}
+
+const earthRadiusMeters = 6371000
+
+// Distance returns the great-circle distance between a and b in meters.
+func Distance(a, b Point) float64 {
+	lat1 := a.Lat * math.Pi / 180
+	lat2 := b.Lat * math.Pi / 180
+	dLat := (b.Lat - a.Lat) * math.Pi / 180
+	dLng := (b.Lng - a.Lng) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(h))
+}
+
+// ParseLatLng parses a "lat,lng" string as produced by the location package.
+func ParseLatLng(s string) (Point, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return Point{}, fmt.Errorf("invalid coordinates %q", s)
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("invalid latitude in %q", s)
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("invalid longitude in %q", s)
+	}
+	if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return Point{}, fmt.Errorf("coordinates out of range %q", s)
+	}
+	return Point{Lat: lat, Lng: lng}, nil
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("%f,%f", p.Lat, p.Lng)
+}
+
+// Offset moves p by the given distances in meters. It uses a flat-earth
+// approximation, which is fine at city scale.
+func Offset(p Point, north, east float64) Point {
+	lat := p.Lat + (north/earthRadiusMeters)*180/math.Pi
+	lng := p.Lng + (east/(earthRadiusMeters*math.Cos(p.Lat*math.Pi/180)))*180/math.Pi
+	return Point{Lat: lat, Lng: lng}
+}
@@ -0,0 +1,89 @@
+package geocode
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"googlemaps.github.io/maps"
+	"seattle-commute-cli/cache"
+	"seattle-commute-cli/geo"
+)
+
+// Addresses don't move; a month keeps the cache small without refetching
+// every day.
+const reverseCacheTTL = 30 * 24 * time.Hour
+
+type ReverseResult struct {
+	Address      string `json:"address"`
+	Neighborhood string `json:"neighborhood,omitempty"`
+}
+
+// String is the short "street (neighborhood)" form used in headers.
+func (r ReverseResult) String() string {
+	street := r.Address
+	if i := strings.Index(street, ","); i > 0 {
+		street = street[:i]
+	}
+	if r.Neighborhood != "" && r.Neighborhood != street {
+		return fmt.Sprintf("%s (%s)", street, r.Neighborhood)
+	}
+	return street
+}
+
+type ReverseGeocoder struct {
+	client *maps.Client
+	cache  *cache.Cache
+}
+
+func NewReverseGeocoder(apiKey string) (*ReverseGeocoder, error) {
+	client, err := maps.NewClient(maps.WithAPIKey(apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Google Maps client: %v", err)
+	}
+	return &ReverseGeocoder{client: client, cache: cache.Open("reverse-geocode", reverseCacheTTL)}, nil
+}
+
+// cacheKey rounds to 4 decimal places (~10 m) so small GPS jitter still hits.
+func cacheKey(pt geo.Point) string {
+	return fmt.Sprintf("%.4f,%.4f", pt.Lat, pt.Lng)
+}
+
+func (rg *ReverseGeocoder) Reverse(pt geo.Point) (*ReverseResult, error) {
+	key := cacheKey(pt)
+
+	var cached ReverseResult
+	if rg.cache.Get(key, &cached) {
+		return &cached, nil
+	}
+
+	ctx := context.Background()
+	req := &maps.GeocodingRequest{
+		LatLng: &maps.LatLng{Lat: pt.Lat, Lng: pt.Lng},
+	}
+
+	resp, err := rg.client.ReverseGeocode(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reverse geocode: %v", err)
+	}
+	if len(resp) == 0 {
+		return nil, fmt.Errorf("no address found near %s", pt)
+	}
+
+	result := &ReverseResult{Address: resp[0].FormattedAddress}
+	for _, r := range resp {
+		for _, component := range r.AddressComponents {
+			for _, typ := range component.Types {
+				if typ == "neighborhood" && result.Neighborhood == "" {
+					result.Neighborhood = component.LongName
+				}
+			}
+		}
+	}
+
+	rg.cache.Set(key, result)
+	rg.cache.Save()
+
+	return result, nil
+}
@@ -2,12 +2,16 @@ module seattle-commute-cli
 
 go 1.24.3
 
+require (
+	github.com/godbus/dbus/v5 v5.1.0
+	github.com/spf13/cobra v1.10.2
+	googlemaps.github.io/maps v1.7.0
+)
+
 require (
 	github.com/google/uuid v1.1.1 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/spf13/cobra v1.10.2 // indirect
 	github.com/spf13/pflag v1.0.9 // indirect
 	go.opencensus.io v0.22.3 // indirect
 	golang.org/x/time v0.0.0-20200416051211-89c76fbcd5d1 // indirect
-	googlemaps.github.io/maps v1.7.0 // indirect
 )
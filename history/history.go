@@ -0,0 +1,103 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"seattle-commute-cli/transit"
+)
+
+// Entry is one query and the best route we showed for it.
+type Entry struct {
+	Time time.Time `json:"time"`
+	From string    `json:"from"`
+	To   string    `json:"to"`
+	// Kind is "home", "work" or "other".
+	Kind      string        `json:"kind"`
+	Depart    time.Time     `json:"depart"`
+	Arrive    time.Time     `json:"arrive"`
+	Duration  time.Duration `json:"duration"`
+	Transit   time.Duration `json:"transit"`
+	Walking   time.Duration `json:"walking"`
+	Transfers int           `json:"transfers"`
+	Lines     []string      `json:"lines,omitempty"`
+	Routes    int           `json:"routes"`
+}
+
+// NewEntry records the best of routes (the first) for a query.
+func NewEntry(from, to, kind string, routes []transit.Route) Entry {
+	best := routes[0]
+	e := Entry{
+		Time:      time.Now(),
+		From:      from,
+		To:        to,
+		Kind:      kind,
+		Depart:    best.DepartureTime,
+		Arrive:    best.ArrivalTime,
+		Duration:  best.Duration,
+		Walking:   best.WalkingTime(),
+		Transfers: best.Transfers(),
+		Lines:     best.Lines(),
+		Routes:    len(routes),
+	}
+	for _, step := range best.TransitSteps() {
+		e.Transit += step.Duration
+	}
+	return e
+}
+
+func Path() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".seattle-commute", "history.jsonl")
+}
+
+// Append adds e to the end of the log.
+func Append(e Entry) error {
+	if err := os.MkdirAll(filepath.Dir(Path()), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(Path(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Load reads the whole log. A missing log is empty; unreadable lines are
+// skipped so one bad write doesn't lose the history.
+func Load() ([]Entry, error) {
+	f, err := os.Open(Path())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %v", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %v", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,116 @@
+package history
+
+import (
+	"sort"
+	"time"
+)
+
+// Average is a running mean of durations.
+type Average struct {
+	Count int
+	Total time.Duration
+}
+
+func (a *Average) Add(d time.Duration) {
+	a.Count++
+	a.Total += d
+}
+
+func (a Average) Mean() time.Duration {
+	if a.Count == 0 {
+		return 0
+	}
+	return a.Total / time.Duration(a.Count)
+}
+
+type LineCount struct {
+	Line  string
+	Count int
+}
+
+type Month struct {
+	// Month is the first of the month in the stats' time zone.
+	Month   time.Time
+	Trips   int
+	Transit time.Duration
+	Average Average
+}
+
+type Stats struct {
+	Trips     int
+	ByWeekday [7]Average
+	Lines     []LineCount
+	// Months are in chronological order.
+	Months []Month
+}
+
+// RepeatWindow is how close together lookups of the same trip count as one.
+const RepeatWindow = 30 * time.Minute
+
+// Collapse merges runs of lookups for the same from and to made within
+// RepeatWindow of each other, keeping the latest of each run. entries must
+// be in time order.
+func Collapse(entries []Entry) []Entry {
+	var kept []Entry
+	for _, e := range entries {
+		if n := len(kept); n > 0 {
+			last := kept[n-1]
+			if last.From == e.From && last.To == e.To && e.Time.Sub(last.Time) <= RepeatWindow {
+				kept[n-1] = e
+				continue
+			}
+		}
+		kept = append(kept, e)
+	}
+	return kept
+}
+
+// Summarize works out statistics for entries, bucketing by day and month in
+// loc.
+func Summarize(entries []Entry, loc *time.Location) Stats {
+	var s Stats
+	lines := make(map[string]int)
+	months := make(map[time.Time]*Month)
+
+	for _, e := range entries {
+		t := e.Depart
+		if t.IsZero() {
+			t = e.Time
+		}
+		t = t.In(loc)
+
+		s.Trips++
+		s.ByWeekday[t.Weekday()].Add(e.Duration)
+		for _, line := range e.Lines {
+			lines[line]++
+		}
+
+		key := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+		m, ok := months[key]
+		if !ok {
+			m = &Month{Month: key}
+			months[key] = m
+		}
+		m.Trips++
+		m.Transit += e.Transit
+		m.Average.Add(e.Duration)
+	}
+
+	for line, n := range lines {
+		s.Lines = append(s.Lines, LineCount{Line: line, Count: n})
+	}
+	sort.Slice(s.Lines, func(i, j int) bool {
+		if s.Lines[i].Count != s.Lines[j].Count {
+			return s.Lines[i].Count > s.Lines[j].Count
+		}
+		return s.Lines[i].Line < s.Lines[j].Line
+	})
+
+	for _, m := range months {
+		s.Months = append(s.Months, *m)
+	}
+	sort.Slice(s.Months, func(i, j int) bool {
+		return s.Months[i].Month.Before(s.Months[j].Month)
+	})
+	return s
+}
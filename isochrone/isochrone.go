@@ -0,0 +1,128 @@
+package isochrone
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"seattle-commute-cli/geo"
+)
+
+// Unreachable marks a point the router found no route to.
+const Unreachable = time.Duration(-1)
+
+// Router returns the travel time from origin to each point, or Unreachable.
+type Router interface {
+	TravelTimes(origin string, points []geo.Point) ([]time.Duration, error)
+}
+
+type Options struct {
+	Center geo.Point
+	// Radius and Step are in meters: how far out to sample and the grid
+	// spacing.
+	Radius float64
+	Step   float64
+	Max    time.Duration
+}
+
+type Cell struct {
+	Center geo.Point
+	Time   time.Duration
+}
+
+type Result struct {
+	Options   Options
+	Reachable []Cell
+	Sampled   int
+}
+
+// Grid returns the points of a square grid centered on center that fall
+// within radius.
+func Grid(center geo.Point, radius, step float64) []geo.Point {
+	n := int(math.Floor(radius / step))
+	var points []geo.Point
+	for i := -n; i <= n; i++ {
+		for j := -n; j <= n; j++ {
+			north, east := float64(i)*step, float64(j)*step
+			if math.Hypot(north, east) > radius {
+				continue
+			}
+			points = append(points, geo.Offset(center, north, east))
+		}
+	}
+	return points
+}
+
+// Compute samples the grid around opts.Center and keeps the points
+// reachable from origin within opts.Max.
+func Compute(router Router, origin string, opts Options) (*Result, error) {
+	if opts.Step <= 0 || opts.Radius < opts.Step {
+		return nil, fmt.Errorf("radius must be at least one grid step")
+	}
+
+	points := Grid(opts.Center, opts.Radius, opts.Step)
+	times, err := router.TravelTimes(origin, points)
+	if err != nil {
+		return nil, err
+	}
+	if len(times) != len(points) {
+		return nil, fmt.Errorf("router returned %d times for %d points", len(times), len(points))
+	}
+
+	result := &Result{Options: opts, Sampled: len(points)}
+	for i, t := range times {
+		if t != Unreachable && t <= opts.Max {
+			result.Reachable = append(result.Reachable, Cell{Center: points[i], Time: t})
+		}
+	}
+	return result, nil
+}
+
+// GeoJSON renders the reachable area as a FeatureCollection holding one
+// MultiPolygon made of the grid cells around each reachable point, plus the
+// origin as a Point.
+func (r *Result) GeoJSON(name string) ([]byte, error) {
+	half := r.Options.Step / 2
+	polygons := make([][][][2]float64, 0, len(r.Reachable))
+	for _, cell := range r.Reachable {
+		sw := geo.Offset(cell.Center, -half, -half)
+		ne := geo.Offset(cell.Center, half, half)
+		ring := [][2]float64{
+			{sw.Lng, sw.Lat},
+			{ne.Lng, sw.Lat},
+			{ne.Lng, ne.Lat},
+			{sw.Lng, ne.Lat},
+			{sw.Lng, sw.Lat},
+		}
+		polygons = append(polygons, [][][2]float64{ring})
+	}
+
+	doc := map[string]any{
+		"type": "FeatureCollection",
+		"features": []any{
+			map[string]any{
+				"type": "Feature",
+				"properties": map[string]any{
+					"name":        name,
+					"max_minutes": r.Options.Max.Minutes(),
+					"reachable":   len(r.Reachable),
+					"sampled":     r.Sampled,
+				},
+				"geometry": map[string]any{
+					"type":        "MultiPolygon",
+					"coordinates": polygons,
+				},
+			},
+			map[string]any{
+				"type":       "Feature",
+				"properties": map[string]any{"name": "origin"},
+				"geometry": map[string]any{
+					"type":        "Point",
+					"coordinates": [2]float64{r.Options.Center.Lng, r.Options.Center.Lat},
+				},
+			},
+		},
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
@@ -0,0 +1,169 @@
+package isochrone
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"seattle-commute-cli/geo"
+)
+
+// stubRouter answers from a fixed table keyed by grid offset: the travel
+// time is a minute per 100 m north or south plus a minute per 100 m east, and
+// everything west of the center is unreachable.
+type stubRouter struct {
+	center geo.Point
+	origin string
+}
+
+func (s *stubRouter) TravelTimes(origin string, points []geo.Point) ([]time.Duration, error) {
+	s.origin = origin
+	times := make([]time.Duration, len(points))
+	for i, pt := range points {
+		north := geo.Distance(s.center, geo.Point{Lat: pt.Lat, Lng: s.center.Lng})
+		east := geo.Distance(s.center, geo.Point{Lat: s.center.Lat, Lng: pt.Lng})
+		if pt.Lng < s.center.Lng-1e-9 {
+			times[i] = Unreachable
+			continue
+		}
+		times[i] = time.Duration((north+east)/100+0.5) * time.Minute
+	}
+	return times, nil
+}
+
+var center = geo.Point{Lat: 47.6062, Lng: -122.3321}
+
+func TestGrid(t *testing.T) {
+	points := Grid(center, 1000, 500)
+	// A 5x5 grid less the twelve points more than 1000 m out
+	if len(points) != 13 {
+		t.Fatalf("got %d points, want 13", len(points))
+	}
+	for _, pt := range points {
+		if d := geo.Distance(center, pt); d > 1000.5 {
+			t.Errorf("%s is %.0f m out, beyond the radius", pt, d)
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	router := &stubRouter{center: center}
+	result, err := Compute(router, "work", Options{Center: center, Radius: 1000, Step: 500, Max: 10 * time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if router.origin != "work" {
+		t.Errorf("router got origin %q", router.origin)
+	}
+	if result.Sampled != 13 {
+		t.Errorf("sampled %d, want 13", result.Sampled)
+	}
+
+	reachable := make(map[string]time.Duration)
+	for _, cell := range result.Reachable {
+		north := geo.Distance(center, geo.Point{Lat: cell.Center.Lat, Lng: center.Lng})
+		east := geo.Distance(center, geo.Point{Lat: center.Lat, Lng: cell.Center.Lng})
+		if cell.Center.Lat < center.Lat {
+			north = -north
+		}
+		reachable[fmt.Sprintf("%.0f,%.0f", north, east)] = cell.Time
+		if cell.Time > 10*time.Minute {
+			t.Errorf("cell %s at %s is over the budget", cell.Center, cell.Time)
+		}
+	}
+
+	// The center column and the east half; the west half is unreachable
+	want := map[string]time.Duration{
+		"0,0": 0, "500,0": 5 * time.Minute, "-500,0": 5 * time.Minute,
+		"1000,0": 10 * time.Minute, "-1000,0": 10 * time.Minute,
+		"0,500": 5 * time.Minute, "500,500": 10 * time.Minute, "-500,500": 10 * time.Minute,
+		"0,1000": 10 * time.Minute,
+	}
+	if len(reachable) != len(want) {
+		t.Errorf("got %d reachable cells %v, want %d", len(reachable), reachable, len(want))
+	}
+	for key, d := range want {
+		got, ok := reachable[key]
+		if !ok {
+			t.Errorf("cell %s should be reachable", key)
+		} else if got != d {
+			t.Errorf("cell %s took %s, want %s", key, got, d)
+		}
+	}
+	for key := range reachable {
+		if _, ok := want[key]; !ok {
+			t.Errorf("cell %s should be unreachable", key)
+		}
+	}
+}
+
+func TestComputeRejectsBadGrid(t *testing.T) {
+	if _, err := Compute(&stubRouter{center: center}, "work", Options{Center: center, Radius: 100, Step: 500, Max: time.Minute}); err == nil {
+		t.Error("want an error when the radius is smaller than a step")
+	}
+}
+
+func TestGeoJSON(t *testing.T) {
+	result, err := Compute(&stubRouter{center: center}, "work", Options{Center: center, Radius: 1000, Step: 500, Max: 10 * time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := result.GeoJSON("work within 10m")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Type       string         `json:"type"`
+			Properties map[string]any `json:"properties"`
+			Geometry   struct {
+				Type        string          `json:"type"`
+				Coordinates json.RawMessage `json:"coordinates"`
+			} `json:"geometry"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Type != "FeatureCollection" || len(doc.Features) != 2 {
+		t.Fatalf("got %s with %d features", doc.Type, len(doc.Features))
+	}
+
+	area := doc.Features[0]
+	if area.Geometry.Type != "MultiPolygon" {
+		t.Fatalf("geometry %s, want MultiPolygon", area.Geometry.Type)
+	}
+	if area.Properties["name"] != "work within 10m" || area.Properties["max_minutes"] != 10.0 {
+		t.Errorf("properties = %v", area.Properties)
+	}
+
+	var polygons [][][][2]float64
+	if err := json.Unmarshal(area.Geometry.Coordinates, &polygons); err != nil {
+		t.Fatal(err)
+	}
+	if len(polygons) != len(result.Reachable) {
+		t.Errorf("%d polygons for %d reachable cells", len(polygons), len(result.Reachable))
+	}
+	for i, polygon := range polygons {
+		if len(polygon) != 1 {
+			t.Fatalf("polygon %d has %d rings, want 1", i, len(polygon))
+		}
+		ring := polygon[0]
+		if len(ring) != 5 || ring[0] != ring[4] {
+			t.Fatalf("polygon %d ring %v is not a closed square", i, ring)
+		}
+		// [lng, lat] order, around the cell center
+		c := result.Reachable[i].Center
+		if ring[0][0] > c.Lng || ring[2][0] < c.Lng || ring[0][1] > c.Lat || ring[2][1] < c.Lat {
+			t.Errorf("polygon %d %v doesn't surround %s", i, ring, c)
+		}
+	}
+
+	origin := doc.Features[1]
+	if origin.Geometry.Type != "Point" {
+		t.Errorf("second feature is a %s, want the origin Point", origin.Geometry.Type)
+	}
+}
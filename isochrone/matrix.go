@@ -0,0 +1,72 @@
+package isochrone
+
+import (
+	"fmt"
+	"time"
+
+	"googlemaps.github.io/maps"
+	"seattle-commute-cli/cache"
+	"seattle-commute-cli/distance"
+	"seattle-commute-cli/geo"
+)
+
+// MatrixRouter answers travel times with batched Distance Matrix requests,
+// caching each point so reruns with a different budget are free.
+type MatrixRouter struct {
+	Checker  *distance.DistanceChecker
+	Mode     maps.Mode
+	DepartAt time.Time
+	Cache    *cache.Cache
+}
+
+func (mr *MatrixRouter) TravelTimes(origin string, points []geo.Point) ([]time.Duration, error) {
+	times := make([]time.Duration, len(points))
+	var missing []int
+
+	for i, pt := range points {
+		var cached time.Duration
+		if mr.Cache != nil && mr.Cache.Get(mr.cacheKey(origin, pt), &cached) {
+			times[i] = cached
+			continue
+		}
+		missing = append(missing, i)
+	}
+
+	if len(missing) > 0 {
+		destinations := make([]string, len(missing))
+		for k, i := range missing {
+			destinations[k] = points[i].String()
+		}
+
+		m, err := mr.Checker.GetMatrix([]string{origin}, destinations, mr.Mode, mr.DepartAt)
+		if err != nil {
+			return nil, err
+		}
+
+		for k, i := range missing {
+			cell := m.Cells[0][k]
+			times[i] = Unreachable
+			if cell.OK() {
+				times[i] = cell.Duration
+			}
+			if mr.Cache != nil {
+				mr.Cache.Set(mr.cacheKey(origin, points[i]), times[i])
+			}
+		}
+		if mr.Cache != nil {
+			mr.Cache.Save()
+		}
+	}
+
+	return times, nil
+}
+
+// cacheKey buckets departures by weekday and time of day, since transit
+// travel times repeat weekly.
+func (mr *MatrixRouter) cacheKey(origin string, pt geo.Point) string {
+	departAt := mr.DepartAt
+	if departAt.IsZero() {
+		departAt = time.Now().Truncate(time.Hour)
+	}
+	return fmt.Sprintf("%s|%s|%s|%.5f,%.5f", origin, mr.Mode, departAt.Format("Mon 15:04"), pt.Lat, pt.Lng)
+}
@@ -0,0 +1,177 @@
+package itinerary
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"googlemaps.github.io/maps"
+	"seattle-commute-cli/distance"
+	"seattle-commute-cli/transit"
+)
+
+// DefaultTransfer is the time allowed to lock up a bike or park.
+const DefaultTransfer = 5 * time.Minute
+
+// Hub is a transit station reachable by bike or car.
+type Hub struct {
+	Name     string
+	Query    string
+	Modes    []maps.Mode
+	Transfer time.Duration
+}
+
+// Planner composes bike-and-ride and park-and-ride itineraries from a
+// distance leg to or from a hub and a transit leg.
+type Planner struct {
+	Distance *distance.DistanceChecker
+	Transit  *transit.TransitService
+	Hubs     []Hub
+	// Accept, if set, rules out transit legs, e.g. ones an accessibility
+	// profile can't take, so the next option is used instead.
+	Accept func(transit.Route) bool
+}
+
+var modeNames = map[maps.Mode]string{
+	maps.TravelModeBicycling: "Bike",
+	maps.TravelModeDriving:   "Drive",
+}
+
+// Plan returns one itinerary per hub and mode: ride/drive to the hub then
+// take transit, and for bikes also transit to the hub then ride the rest
+// (cars can't be picked up at the far end). Hubs that fail or only yield a
+// walk are skipped.
+func (p *Planner) Plan(origin, destination string) []transit.Route {
+	var mu sync.Mutex
+	var routes []transit.Route
+	var wg sync.WaitGroup
+
+	add := func(r *transit.Route, err error) {
+		if err != nil || r == nil {
+			return
+		}
+		mu.Lock()
+		routes = append(routes, *r)
+		mu.Unlock()
+	}
+
+	for _, hub := range p.Hubs {
+		for _, mode := range hub.Modes {
+			if _, ok := modeNames[mode]; !ok {
+				continue
+			}
+			wg.Add(1)
+			go func(hub Hub, mode maps.Mode) {
+				defer wg.Done()
+				add(p.firstMile(origin, destination, hub, mode))
+			}(hub, mode)
+
+			if mode == maps.TravelModeBicycling {
+				wg.Add(1)
+				go func(hub Hub) {
+					defer wg.Done()
+					add(p.lastMile(origin, destination, hub))
+				}(hub)
+			}
+		}
+	}
+	wg.Wait()
+
+	return routes
+}
+
+func (p *Planner) firstMile(origin, destination string, hub Hub, mode maps.Mode) (*transit.Route, error) {
+	access, accessDistance, err := p.Distance.GetTravelTime(origin, hub.Query, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer := hub.transfer()
+	earliest := time.Now().Add(access + transfer)
+	ride, err := p.firstTransitRoute(hub.Query, destination, earliest)
+	if err != nil {
+		return nil, err
+	}
+
+	// Leave just in time for the transit departure
+	arriveHub := ride.DepartureTime.Add(-transfer)
+	leave := arriveHub.Add(-access)
+
+	step := transit.Step{
+		Instructions: fmt.Sprintf("%s to %s", modeNames[mode], hub.Name),
+		Duration:     access,
+		Mode:         strings.ToUpper(string(mode)),
+		LineInfo:     modeNames[mode],
+		DepartTime:   leave,
+		ArrivalTime:  arriveHub,
+	}
+
+	return &transit.Route{
+		Summary:       fmt.Sprintf("%s to %s + %s", modeNames[mode], hub.Name, ride.Summary),
+		Duration:      ride.ArrivalTime.Sub(leave),
+		DepartureTime: leave,
+		ArrivalTime:   ride.ArrivalTime,
+		Steps:         append([]transit.Step{step}, ride.Steps...),
+		Distance:      fmt.Sprintf("%s + %s", accessDistance, ride.Distance),
+	}, nil
+}
+
+func (p *Planner) lastMile(origin, destination string, hub Hub) (*transit.Route, error) {
+	ride, err := p.firstTransitRoute(origin, hub.Query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	egress, egressDistance, err := p.Distance.GetTravelTime(hub.Query, destination, maps.TravelModeBicycling)
+	if err != nil {
+		return nil, err
+	}
+
+	leaveHub := ride.ArrivalTime.Add(hub.transfer())
+	arrive := leaveHub.Add(egress)
+
+	step := transit.Step{
+		Instructions: fmt.Sprintf("Bike from %s", hub.Name),
+		Duration:     egress,
+		Mode:         strings.ToUpper(string(maps.TravelModeBicycling)),
+		LineInfo:     modeNames[maps.TravelModeBicycling],
+		DepartTime:   leaveHub,
+		ArrivalTime:  arrive,
+	}
+
+	return &transit.Route{
+		Summary:       fmt.Sprintf("%s + bike from %s", ride.Summary, hub.Name),
+		Duration:      arrive.Sub(ride.DepartureTime),
+		DepartureTime: ride.DepartureTime,
+		ArrivalTime:   arrive,
+		Steps:         append(ride.Steps, step),
+		Distance:      fmt.Sprintf("%s + %s", ride.Distance, egressDistance),
+	}, nil
+}
+
+// firstTransitRoute returns the earliest route that actually uses transit.
+func (p *Planner) firstTransitRoute(origin, destination string, departAt time.Time) (*transit.Route, error) {
+	routes, err := p.Transit.GetRoutesAt(origin, destination, departAt)
+	if err != nil {
+		return nil, err
+	}
+	for i := range routes {
+		if p.Accept != nil && !p.Accept(routes[i]) {
+			continue
+		}
+		for _, step := range routes[i].Steps {
+			if step.Mode == "TRANSIT" {
+				return &routes[i], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no transit between %s and %s", origin, destination)
+}
+
+func (h Hub) transfer() time.Duration {
+	if h.Transfer > 0 {
+		return h.Transfer
+	}
+	return DefaultTransfer
+}
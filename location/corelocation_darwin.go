@@ -0,0 +1,72 @@
+//go:build darwin && cgo
+
+package location
+
+/*
+#cgo CFLAGS: -x objective-c -mmacosx-version-min=10.14
+#cgo LDFLAGS: -framework CoreLocation -framework Foundation
+#include "corelocation_darwin.h"
+*/
+import "C"
+import (
+	"context"
+	"fmt"
+	"runtime"
+)
+
+func init() {
+	if runtime.GOOS == "darwin" {
+		C.initLocationManager()
+	}
+}
+
+func GetPreciseLocation(ctx context.Context) (*Fix, error) {
+	if runtime.GOOS != "darwin" {
+		return nil, fmt.Errorf("Core Location only available on macOS")
+	}
+
+	// requestLocation blocks for up to ten seconds, so wait on it alongside
+	// ctx; an abandoned request finishes in the background and is dropped.
+	type answer struct {
+		fix *Fix
+		err error
+	}
+	done := make(chan answer, 1)
+	go func() {
+		fix, err := requestLocation()
+		done <- answer{fix, err}
+	}()
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("location request cancelled: %v", ctx.Err())
+	case a := <-done:
+		return a.fix, a.err
+	}
+}
+
+func requestLocation() (*Fix, error) {
+	result := C.requestLocation()
+
+	switch result {
+	case 0:
+		// Success
+		return &Fix{
+			Lat:      float64(C.getLatitude()),
+			Lng:      float64(C.getLongitude()),
+			Accuracy: float64(C.getAccuracy()),
+			Source:   "corelocation",
+		}, nil
+	case -1:
+		return nil, fmt.Errorf("location manager not initialized")
+	case -2:
+		return nil, fmt.Errorf("location permission denied. Please allow location access for this app in System Preferences > Security & Privacy > Privacy > Location Services")
+	case -3:
+		return nil, fmt.Errorf("location services disabled. Please enable Location Services in System Preferences > Security & Privacy > Privacy > Location Services")
+	case -4:
+		return nil, fmt.Errorf("failed to get location (GPS/WiFi issue)")
+	case -5:
+		return nil, fmt.Errorf("location request timed out")
+	default:
+		return nil, fmt.Errorf("unknown location error (%d)", result)
+	}
+}
\ No newline at end of file
@@ -5,5 +5,6 @@ void initLocationManager();
 int requestLocation();
 double getLatitude();
 double getLongitude();
+double getAccuracy();
 
 #endif
\ No newline at end of file
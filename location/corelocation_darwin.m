@@ -5,6 +5,7 @@
 @property (nonatomic, strong) CLLocationManager *locationManager;
 @property (nonatomic) double latitude;
 @property (nonatomic) double longitude;
+@property (nonatomic) double accuracy;
 @property (nonatomic) BOOL locationReceived;
 @property (nonatomic, strong) NSError *locationError;
 @end
@@ -26,6 +27,7 @@
     CLLocation *location = [locations lastObject];
     self.latitude = location.coordinate.latitude;
     self.longitude = location.coordinate.longitude;
+    self.accuracy = location.horizontalAccuracy;
     self.locationReceived = YES;
     [self.locationManager stopUpdatingLocation];
 }
@@ -92,4 +94,8 @@ double getLatitude() {
 
 double getLongitude() {
     return locationDelegate ? locationDelegate.longitude : 0.0;
+}
+
+double getAccuracy() {
+    return locationDelegate ? locationDelegate.accuracy : 0.0;
 }
\ No newline at end of file
@@ -0,0 +1,123 @@
+//go:build linux
+
+package location
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	geoclueService   = "org.freedesktop.GeoClue2"
+	geoclueManager   = "/org/freedesktop/GeoClue2/Manager"
+	geoclueClientIfc = "org.freedesktop.GeoClue2.Client"
+	geoclueLocIfc    = "org.freedesktop.GeoClue2.Location"
+
+	// GCLUE_ACCURACY_LEVEL_EXACT
+	geoclueAccuracyExact uint32 = 8
+)
+
+// GeoClueDesktopID identifies us to GeoClue's agent. GeoClue only answers
+// apps it knows about, so this needs a matching .desktop file or a
+// [seattle-commute] section allowing it in /etc/geoclue/geoclue.conf.
+var GeoClueDesktopID = "seattle-commute"
+
+// GeoClue finds the location through GeoClue2 on the given bus. Connect
+// defaults to the system bus; tests can point it at a private bus serving a
+// fake GeoClue.
+type GeoClue struct {
+	Connect func() (*dbus.Conn, error)
+	Timeout time.Duration
+}
+
+func GetPreciseLocation(ctx context.Context) (*Fix, error) {
+	g := &GeoClue{
+		Connect: func() (*dbus.Conn, error) { return dbus.ConnectSystemBus() },
+		Timeout: 10 * time.Second,
+	}
+	return g.Locate(ctx)
+}
+
+func (g *GeoClue) Locate(ctx context.Context) (*Fix, error) {
+	conn, err := g.Connect()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to D-Bus: %v", err)
+	}
+	defer conn.Close()
+
+	if g.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, g.Timeout)
+		defer cancel()
+	}
+
+	var clientPath dbus.ObjectPath
+	manager := conn.Object(geoclueService, geoclueManager)
+	if err := manager.CallWithContext(ctx, "org.freedesktop.GeoClue2.Manager.GetClient", 0).Store(&clientPath); err != nil {
+		return nil, fmt.Errorf("GeoClue unavailable: %v", err)
+	}
+	client := conn.Object(geoclueService, clientPath)
+
+	if err := client.SetProperty(geoclueClientIfc+".DesktopId", dbus.MakeVariant(GeoClueDesktopID)); err != nil {
+		return nil, fmt.Errorf("failed to register with GeoClue: %v", err)
+	}
+	if err := client.SetProperty(geoclueClientIfc+".RequestedAccuracyLevel", dbus.MakeVariant(geoclueAccuracyExact)); err != nil {
+		return nil, fmt.Errorf("failed to register with GeoClue: %v", err)
+	}
+
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(clientPath),
+		dbus.WithMatchInterface(geoclueClientIfc),
+		dbus.WithMatchMember("LocationUpdated"),
+	); err != nil {
+		return nil, fmt.Errorf("failed to watch GeoClue: %v", err)
+	}
+	signals := make(chan *dbus.Signal, 4)
+	conn.Signal(signals)
+
+	if err := client.CallWithContext(ctx, geoclueClientIfc+".Start", 0).Err; err != nil {
+		return nil, fmt.Errorf("location permission denied or GeoClue failed to start: %v", err)
+	}
+	defer client.Call(geoclueClientIfc+".Stop", 0)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("location request timed out")
+		case sig := <-signals:
+			if sig.Path != clientPath || sig.Name != geoclueClientIfc+".LocationUpdated" || len(sig.Body) < 2 {
+				continue
+			}
+			locPath, ok := sig.Body[1].(dbus.ObjectPath)
+			if !ok {
+				continue
+			}
+			return readGeoClueLocation(conn.Object(geoclueService, locPath))
+		}
+	}
+}
+
+func readGeoClueLocation(obj dbus.BusObject) (*Fix, error) {
+	var fix Fix
+	for _, prop := range []struct {
+		name string
+		dst  *float64
+	}{
+		{"Latitude", &fix.Lat},
+		{"Longitude", &fix.Lng},
+		{"Accuracy", &fix.Accuracy},
+	} {
+		v, err := obj.GetProperty(geoclueLocIfc + "." + prop.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read GeoClue location: %v", err)
+		}
+		if err := v.Store(prop.dst); err != nil {
+			return nil, fmt.Errorf("failed to read GeoClue location: %v", err)
+		}
+	}
+	fix.Source = "geoclue"
+	return &fix, nil
+}
@@ -0,0 +1,166 @@
+//go:build linux
+
+package location
+
+import (
+	"bufio"
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/prop"
+)
+
+const (
+	fakeClientPath   dbus.ObjectPath = "/org/freedesktop/GeoClue2/Client/1"
+	fakeLocationPath dbus.ObjectPath = "/org/freedesktop/GeoClue2/Location/1"
+)
+
+// privateBus starts a throwaway dbus-daemon and returns its address.
+func privateBus(t *testing.T) string {
+	t.Helper()
+	daemon, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon not installed")
+	}
+	cmd := exec.Command(daemon, "--session", "--nofork", "--print-address")
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("failed to start dbus-daemon: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	addr, err := bufio.NewReader(out).ReadString('\n')
+	if err != nil {
+		t.Skipf("dbus-daemon printed no address: %v", err)
+	}
+	return strings.TrimSpace(addr)
+}
+
+// fakeGeoClue serves just enough of GeoClue2 for GeoClue.Locate: a manager
+// handing out one client, and a client that reports a fixed location once
+// started, unless silent.
+type fakeGeoClue struct {
+	conn   *dbus.Conn
+	client *prop.Properties
+	silent bool
+}
+
+func (f *fakeGeoClue) GetClient() (dbus.ObjectPath, *dbus.Error) {
+	return fakeClientPath, nil
+}
+
+func (f *fakeGeoClue) Start() *dbus.Error {
+	if !f.silent {
+		go f.conn.Emit(fakeClientPath, geoclueClientIfc+".LocationUpdated", dbus.ObjectPath("/"), fakeLocationPath)
+	}
+	return nil
+}
+
+func (f *fakeGeoClue) Stop() *dbus.Error {
+	return nil
+}
+
+func serveFakeGeoClue(t *testing.T, addr string, silent bool) *fakeGeoClue {
+	t.Helper()
+	conn, err := dbus.Connect(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	f := &fakeGeoClue{conn: conn, silent: silent}
+	if err := conn.Export(f, geoclueManager, "org.freedesktop.GeoClue2.Manager"); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Export(f, fakeClientPath, geoclueClientIfc); err != nil {
+		t.Fatal(err)
+	}
+	f.client, err = prop.Export(conn, fakeClientPath, prop.Map{
+		geoclueClientIfc: {
+			"DesktopId":              {Value: "", Writable: true, Emit: prop.EmitFalse},
+			"RequestedAccuracyLevel": {Value: uint32(0), Writable: true, Emit: prop.EmitFalse},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := prop.Export(conn, fakeLocationPath, prop.Map{
+		geoclueLocIfc: {
+			"Latitude":  {Value: 47.6097, Emit: prop.EmitFalse},
+			"Longitude": {Value: -122.3331, Emit: prop.EmitFalse},
+			"Accuracy":  {Value: 25.0, Emit: prop.EmitFalse},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	reply, err := conn.RequestName(geoclueService, dbus.NameFlagDoNotQueue)
+	if err != nil || reply != dbus.RequestNameReplyPrimaryOwner {
+		t.Fatalf("failed to own %s: %v", geoclueService, err)
+	}
+	return f
+}
+
+func TestGeoClueLocate(t *testing.T) {
+	addr := privateBus(t)
+	fake := serveFakeGeoClue(t, addr, false)
+
+	g := &GeoClue{
+		Connect: func() (*dbus.Conn, error) { return dbus.Connect(addr) },
+		Timeout: 5 * time.Second,
+	}
+	fix, err := g.Locate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fix.Lat != 47.6097 || fix.Lng != -122.3331 || fix.Accuracy != 25 || fix.Source != "geoclue" {
+		t.Errorf("got %+v", fix)
+	}
+	if id := fake.client.GetMust(geoclueClientIfc, "DesktopId"); id != GeoClueDesktopID {
+		t.Errorf("DesktopId = %v, want %s", id, GeoClueDesktopID)
+	}
+	if level := fake.client.GetMust(geoclueClientIfc, "RequestedAccuracyLevel"); level != geoclueAccuracyExact {
+		t.Errorf("RequestedAccuracyLevel = %v, want %d", level, geoclueAccuracyExact)
+	}
+}
+
+func TestGeoClueLocateHonoursCancellation(t *testing.T) {
+	addr := privateBus(t)
+	serveFakeGeoClue(t, addr, true)
+
+	g := &GeoClue{
+		Connect: func() (*dbus.Conn, error) { return dbus.Connect(addr) },
+		Timeout: time.Minute,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := g.Locate(ctx); err == nil {
+		t.Fatal("want an error when no location arrives")
+	}
+	if waited := time.Since(start); waited > 5*time.Second {
+		t.Errorf("waited %s after the context was cancelled", waited)
+	}
+}
+
+func TestGeoClueUnavailable(t *testing.T) {
+	addr := privateBus(t)
+
+	g := &GeoClue{
+		Connect: func() (*dbus.Conn, error) { return dbus.Connect(addr) },
+		Timeout: 5 * time.Second,
+	}
+	if _, err := g.Locate(context.Background()); err == nil || !strings.Contains(err.Error(), "GeoClue unavailable") {
+		t.Errorf("got %v, want GeoClue unavailable", err)
+	}
+}
@@ -1,10 +1,14 @@
 package location
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
+
+	"seattle-commute-cli/geo"
 )
 
 type IPLocation struct {
@@ -22,86 +26,253 @@ type IPLocation struct {
 	Message     string  `json:"message"`
 }
 
-func GetCurrentLocation() (string, error) {
-	location, err := tryLocationServices()
-	if err != nil {
-		return "", fmt.Errorf("automatic location detection failed: %v\n\n💡 Try: 'commute --from \"your current address\"' or set a default location with 'commute config set-current \"address\"'", err)
+// Fix is a location reported by a provider.
+type Fix struct {
+	Lat float64
+	Lng float64
+	// Accuracy is the radius in meters the true location is likely within;
+	// zero means the provider doesn't know.
+	Accuracy float64
+	Source   string
+	// Fallback marks a made-up location such as the region center.
+	Fallback bool
+	// Place is set when the provider recognized a saved place.
+	Place string
+}
+
+func (f *Fix) Point() geo.Point {
+	return geo.Point{Lat: f.Lat, Lng: f.Lng}
+}
+
+func (f *Fix) String() string {
+	return fmt.Sprintf("%f,%f", f.Lat, f.Lng)
+}
+
+type Confidence int
+
+const (
+	ConfidenceNone Confidence = iota
+	ConfidenceLow
+	ConfidenceMedium
+	ConfidenceHigh
+)
+
+func (c Confidence) String() string {
+	switch c {
+	case ConfidenceHigh:
+		return "high"
+	case ConfidenceMedium:
+		return "medium"
+	case ConfidenceLow:
+		return "low"
+	default:
+		return "none"
+	}
+}
+
+func ParseConfidence(s string) (Confidence, error) {
+	switch strings.ToLower(s) {
+	case "none":
+		return ConfidenceNone, nil
+	case "", "low":
+		return ConfidenceLow, nil
+	case "medium":
+		return ConfidenceMedium, nil
+	case "high":
+		return ConfidenceHigh, nil
 	}
-	return location, nil
+	return ConfidenceNone, fmt.Errorf("unknown confidence %q (use none, low, medium or high)", s)
 }
 
-func tryLocationServices() (string, error) {
-	services := []func() (string, error){
-		tryPreciseLocation,
-		tryIPAPI,
-		tryIPInfo,
-		tryDefault,
+// Confidence buckets the fix by accuracy: street level is high, neighborhood
+// is medium, anything coarser (or unknown) is low.
+func (f *Fix) Confidence() Confidence {
+	switch {
+	case f.Fallback:
+		return ConfidenceNone
+	case f.Accuracy <= 0:
+		return ConfidenceLow
+	case f.Accuracy <= 100:
+		return ConfidenceHigh
+	case f.Accuracy <= 1000:
+		return ConfidenceMedium
+	default:
+		return ConfidenceLow
 	}
+}
+
+type Provider interface {
+	Name() string
+	Locate(ctx context.Context) (*Fix, error)
+}
 
-	for _, service := range services {
-		if location, err := service(); err == nil {
-			return location, nil
+// DefaultCenter is the last-resort location, normally the center of the
+// configured region.
+var DefaultCenter = geo.Point{Lat: 47.6062, Lng: -122.3321}
+
+// DefaultOrder is the provider chain used when none is configured.
+var DefaultOrder = []string{"network", "precise", "ip-api", "ipinfo", "default"}
+
+var providers = map[string]Provider{}
+
+// Register makes a provider available to chains by name.
+func Register(p Provider) {
+	providers[p.Name()] = p
+}
+
+func init() {
+	Register(Networks)
+	Register(providerFunc{"precise", locatePrecise})
+	Register(providerFunc{"ip-api", locateIPAPI})
+	Register(providerFunc{"ipinfo", locateIPInfo})
+	Register(providerFunc{"default", locateDefault})
+}
+
+// ProviderNames lists the registered providers in default order first.
+func ProviderNames() []string {
+	names := append([]string(nil), DefaultOrder...)
+	for name := range providers {
+		found := false
+		for _, n := range names {
+			if n == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			names = append(names, name)
 		}
 	}
+	return names
+}
 
-	return "", fmt.Errorf("all location services failed")
+type providerFunc struct {
+	name   string
+	locate func(ctx context.Context) (*Fix, error)
 }
 
-func tryPreciseLocation() (string, error) {
-	return GetPreciseLocation()
+func (p providerFunc) Name() string {
+	return p.name
 }
 
-func tryIPAPI() (string, error) {
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get("https://ip-api.com/json/")
-	if err != nil {
-		return "", err
+func (p providerFunc) Locate(ctx context.Context) (*Fix, error) {
+	return p.locate(ctx)
+}
+
+// Chain tries providers in order and returns the first fix.
+type Chain struct {
+	Providers []Provider
+}
+
+// NewChain builds a chain from provider names, skipping any in disabled. An
+// empty order uses DefaultOrder.
+func NewChain(order, disabled []string) (*Chain, error) {
+	if len(order) == 0 {
+		order = DefaultOrder
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("status: %d", resp.StatusCode)
+	skip := make(map[string]bool, len(disabled))
+	for _, name := range disabled {
+		skip[strings.ToLower(name)] = true
 	}
 
-	var loc IPLocation
-	if err := json.NewDecoder(resp.Body).Decode(&loc); err != nil {
-		return "", err
+	chain := &Chain{}
+	for _, name := range order {
+		name = strings.ToLower(name)
+		p, ok := providers[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown location provider %q (available: %s)", name, strings.Join(ProviderNames(), ", "))
+		}
+		if !skip[name] {
+			chain.Providers = append(chain.Providers, p)
+		}
 	}
 
-	if loc.Status != "success" {
-		return "", fmt.Errorf(loc.Message)
+	if len(chain.Providers) == 0 {
+		return nil, fmt.Errorf("all location providers are disabled")
 	}
+	return chain, nil
+}
 
-	return fmt.Sprintf("%f,%f", loc.Lat, loc.Lon), nil
+func (c *Chain) Locate(ctx context.Context) (*Fix, error) {
+	var failures []string
+	for _, p := range c.Providers {
+		fix, err := p.Locate(ctx)
+		if err == nil {
+			if fix.Source == "" {
+				fix.Source = p.Name()
+			}
+			return fix, nil
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", p.Name(), err))
+	}
+
+	return nil, fmt.Errorf("automatic location detection failed (%s)\n\n💡 Try: 'commute --from \"your current address\"'", strings.Join(failures, "; "))
 }
 
-func tryIPInfo() (string, error) {
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get("https://ipinfo.io/json")
-	if err != nil {
-		return "", err
+func locatePrecise(ctx context.Context) (*Fix, error) {
+	return GetPreciseLocation(ctx)
+}
+
+// IP geolocation is city level at best.
+const ipAccuracyMeters = 5000
+
+func locateIPAPI(ctx context.Context) (*Fix, error) {
+	var loc IPLocation
+	if err := getJSON(ctx, "https://ip-api.com/json/", &loc); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("status: %d", resp.StatusCode)
+	if loc.Status != "success" {
+		return nil, fmt.Errorf("%s", loc.Message)
 	}
 
+	return &Fix{Lat: loc.Lat, Lng: loc.Lon, Accuracy: ipAccuracyMeters, Source: "ip-api"}, nil
+}
+
+func locateIPInfo(ctx context.Context) (*Fix, error) {
 	var result struct {
 		Loc string `json:"loc"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", err
+	if err := getJSON(ctx, "https://ipinfo.io/json", &result); err != nil {
+		return nil, err
 	}
 
 	if result.Loc == "" {
-		return "", fmt.Errorf("no location data")
+		return nil, fmt.Errorf("no location data")
 	}
 
-	return result.Loc, nil
+	pt, err := geo.ParseLatLng(result.Loc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Fix{Lat: pt.Lat, Lng: pt.Lng, Accuracy: ipAccuracyMeters, Source: "ipinfo"}, nil
+}
+
+func locateDefault(ctx context.Context) (*Fix, error) {
+	return &Fix{Lat: DefaultCenter.Lat, Lng: DefaultCenter.Lng, Source: "default", Fallback: true}, nil
 }
 
-func tryDefault() (string, error) {
-	return "47.6062,-122.3321", nil
-}
\ No newline at end of file
+func getJSON(ctx context.Context, url string, v any) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("status: %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,214 @@
+package location
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// NetworkFingerprint describes the local network we're on. Any field may be
+// empty if it couldn't be determined.
+type NetworkFingerprint struct {
+	SSID       string
+	GatewayMAC string
+	Custom     string
+}
+
+func (f NetworkFingerprint) IsEmpty() bool {
+	return f.SSID == "" && f.GatewayMAC == "" && f.Custom == ""
+}
+
+// KnownNetwork is a saved place and the fingerprints learned there.
+type KnownNetwork struct {
+	Place       string
+	Lat         float64
+	Lng         float64
+	SSIDs       []string
+	GatewayMACs []string
+	Custom      []string
+}
+
+// NetworkProvider recognizes saved places by the network we're connected to.
+type NetworkProvider struct {
+	Known []KnownNetwork
+	// Command, if set, is run through the shell and its trimmed output is
+	// used as an extra fingerprint (e.g. a VPN or docking station ID).
+	Command string
+}
+
+// Networks is the registered network provider; configure it before
+// building a chain.
+var Networks = &NetworkProvider{}
+
+// Wi-Fi range, roughly.
+const networkAccuracyMeters = 50
+
+func (np *NetworkProvider) Name() string {
+	return "network"
+}
+
+func (np *NetworkProvider) Locate(ctx context.Context) (*Fix, error) {
+	if len(np.Known) == 0 {
+		return nil, fmt.Errorf("no networks learned (run 'commute places learn <place>')")
+	}
+
+	current := np.Current(ctx)
+	if current.IsEmpty() {
+		return nil, fmt.Errorf("couldn't read network fingerprint")
+	}
+
+	known, err := np.Match(current)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Fix{
+		Lat:      known.Lat,
+		Lng:      known.Lng,
+		Accuracy: networkAccuracyMeters,
+		Source:   "network",
+		Place:    known.Place,
+	}, nil
+}
+
+// Match finds the place whose learned fingerprints match current. Gateway
+// MACs identify a network most precisely, then custom IDs, then SSIDs, which
+// are often shared (e.g. a corporate SSID at every office).
+func (np *NetworkProvider) Match(current NetworkFingerprint) (*KnownNetwork, error) {
+	checks := []struct {
+		value string
+		list  func(k *KnownNetwork) []string
+	}{
+		{strings.ToLower(current.GatewayMAC), func(k *KnownNetwork) []string { return k.GatewayMACs }},
+		{current.Custom, func(k *KnownNetwork) []string { return k.Custom }},
+		{current.SSID, func(k *KnownNetwork) []string { return k.SSIDs }},
+	}
+
+	for _, check := range checks {
+		if check.value == "" {
+			continue
+		}
+		var matches []*KnownNetwork
+		for i := range np.Known {
+			if containsFold(check.list(&np.Known[i]), check.value) {
+				matches = append(matches, &np.Known[i])
+			}
+		}
+		if len(matches) == 1 {
+			return matches[0], nil
+		}
+		if len(matches) > 1 {
+			names := make([]string, 0, len(matches))
+			for _, m := range matches {
+				names = append(names, m.Place)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("network %q matches several places (%s)", check.value, strings.Join(names, ", "))
+		}
+	}
+
+	return nil, fmt.Errorf("current network isn't a known place")
+}
+
+func containsFold(list []string, value string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Current reads the fingerprint of the network we're on now.
+func (np *NetworkProvider) Current(ctx context.Context) NetworkFingerprint {
+	var fp NetworkFingerprint
+
+	if out, err := exec.CommandContext(ctx, "nmcli", "-t", "-f", "active,ssid", "dev", "wifi").Output(); err == nil {
+		fp.SSID = parseNmcliSSID(string(out))
+	}
+	if fp.SSID == "" {
+		if out, err := exec.CommandContext(ctx, "iw", "dev").Output(); err == nil {
+			fp.SSID = parseIwSSID(string(out))
+		}
+	}
+
+	if routes, err := os.ReadFile("/proc/net/route"); err == nil {
+		if gw := parseDefaultGateway(string(routes)); gw != "" {
+			if arp, err := os.ReadFile("/proc/net/arp"); err == nil {
+				fp.GatewayMAC = parseARPEntry(string(arp), gw)
+			}
+		}
+	}
+
+	if np.Command != "" {
+		if out, err := exec.CommandContext(ctx, "sh", "-c", np.Command).Output(); err == nil {
+			fp.Custom = strings.TrimSpace(string(out))
+		}
+	}
+
+	return fp
+}
+
+// parseNmcliSSID reads `nmcli -t -f active,ssid dev wifi` output, e.g.
+// "yes:HomeNet". Colons in the SSID are escaped as "\:".
+func parseNmcliSSID(out string) string {
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "yes:") {
+			return strings.ReplaceAll(strings.TrimPrefix(line, "yes:"), `\:`, ":")
+		}
+	}
+	return ""
+}
+
+// parseIwSSID reads the first "ssid <name>" line of `iw dev` output.
+func parseIwSSID(out string) string {
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "ssid ") {
+			return strings.TrimPrefix(line, "ssid ")
+		}
+	}
+	return ""
+}
+
+// parseDefaultGateway finds the default route in /proc/net/route, where the
+// gateway is a little-endian hex IPv4 address.
+func parseDefaultGateway(routes string) string {
+	scanner := bufio.NewScanner(strings.NewReader(routes))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || fields[1] != "00000000" {
+			continue
+		}
+		raw, err := hex.DecodeString(fields[2])
+		if err != nil || len(raw) != 4 {
+			continue
+		}
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, binary.LittleEndian.Uint32(raw))
+		return ip.String()
+	}
+	return ""
+}
+
+// parseARPEntry returns the MAC for ip from /proc/net/arp.
+func parseARPEntry(arp, ip string) string {
+	scanner := bufio.NewScanner(strings.NewReader(arp))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 4 && fields[0] == ip && fields[3] != "00:00:00:00:00:00" {
+			return strings.ToLower(fields[3])
+		}
+	}
+	return ""
+}
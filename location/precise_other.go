@@ -0,0 +1,13 @@
+//go:build !linux && !(darwin && cgo)
+
+package location
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+)
+
+func GetPreciseLocation(ctx context.Context) (*Fix, error) {
+	return nil, fmt.Errorf("precise location is not supported on %s", runtime.GOOS)
+}
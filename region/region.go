@@ -0,0 +1,191 @@
+package region
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"seattle-commute-cli/geo"
+)
+
+//go:embed regions
+var builtinRegions embed.FS
+
+const Default = "seattle"
+
+type Bounds struct {
+	North float64 `json:"north"`
+	South float64 `json:"south"`
+	East  float64 `json:"east"`
+	West  float64 `json:"west"`
+}
+
+func (b Bounds) Contains(pt geo.Point) bool {
+	return pt.Lat <= b.North && pt.Lat >= b.South && pt.Lng <= b.East && pt.Lng >= b.West
+}
+
+type LatLng struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+func (ll LatLng) Point() geo.Point {
+	return geo.Point{Lat: ll.Lat, Lng: ll.Lng}
+}
+
+func (ll LatLng) String() string {
+	return fmt.Sprintf("%f,%f", ll.Lat, ll.Lng)
+}
+
+// Region describes a metro area the tool can route in: where it is, which
+// agencies serve it and what to tell users when transit isn't running.
+type Region struct {
+	Name         string   `json:"name"`
+	DisplayName  string   `json:"display_name"`
+	TimeZone     string   `json:"time_zone"`
+	Bounds       Bounds   `json:"bounds"`
+	Center       LatLng   `json:"center"`
+	Agencies     []string `json:"agencies"`
+	ServiceHours string   `json:"service_hours"`
+	ServiceArea  string   `json:"service_area"`
+	// FareTable names the fare table used to price routes, e.g. "orca".
+	FareTable string `json:"fare_table,omitempty"`
+
+	// Features is the parsed service area, loaded from ServiceArea.
+	Features []geo.Feature `json:"-"`
+	location *time.Location
+}
+
+// RegionsDir is where custom region definitions are looked up by name.
+func RegionsDir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".seattle-commute", "regions")
+}
+
+// Load resolves a region by built-in name, by name of a file in RegionsDir,
+// or by path to a region JSON file. A path to a bare GeoJSON file keeps the
+// default region and only replaces its service area. An empty name loads
+// the default region.
+func Load(nameOrPath string) (*Region, error) {
+	if nameOrPath == "" {
+		nameOrPath = Default
+	}
+	lower := strings.ToLower(nameOrPath)
+
+	if strings.HasSuffix(lower, ".geojson") {
+		r, err := Load(Default)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.loadServiceArea(os.ReadFile, nameOrPath); err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+
+	if strings.HasSuffix(lower, ".json") {
+		return loadFile(os.ReadFile, nameOrPath)
+	}
+
+	custom := filepath.Join(RegionsDir(), lower+".json")
+	if _, err := os.Stat(custom); err == nil {
+		return loadFile(os.ReadFile, custom)
+	}
+
+	r, err := loadFile(builtinRegions.ReadFile, "regions/"+lower+".json")
+	if err != nil {
+		return nil, fmt.Errorf("unknown region %q (add %s to define it)", nameOrPath, custom)
+	}
+	return r, nil
+}
+
+func loadFile(readFile func(string) ([]byte, error), path string) (*Region, error) {
+	data, err := readFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read region: %v", err)
+	}
+
+	var r Region
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("invalid region %s: %v", path, err)
+	}
+	if r.Name == "" {
+		r.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if r.DisplayName == "" {
+		r.DisplayName = r.Name
+	}
+
+	if r.TimeZone != "" {
+		loc, err := time.LoadLocation(r.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone in region %s: %v", r.Name, err)
+		}
+		r.location = loc
+	}
+
+	if r.ServiceArea != "" {
+		areaPath := r.ServiceArea
+		if !filepath.IsAbs(areaPath) {
+			// ToSlash keeps embedded paths valid on Windows.
+			areaPath = filepath.ToSlash(filepath.Join(filepath.Dir(path), areaPath))
+		}
+		if err := r.loadServiceArea(readFile, areaPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return &r, nil
+}
+
+func (r *Region) loadServiceArea(readFile func(string) ([]byte, error), path string) error {
+	data, err := readFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read service area: %v", err)
+	}
+	features, err := geo.ParseGeoJSON(data)
+	if err != nil {
+		return fmt.Errorf("failed to load service area %s: %v", path, err)
+	}
+	r.ServiceArea = path
+	r.Features = features
+	return nil
+}
+
+// Location returns the region's time zone, or the local zone if unset.
+func (r *Region) Location() *time.Location {
+	if r.location != nil {
+		return r.location
+	}
+	return time.Local
+}
+
+// Locate returns the name of the service area district containing pt. A
+// region without a service area falls back to its bounding box, and one
+// with neither accepts everything.
+func (r *Region) Locate(pt geo.Point) (string, bool) {
+	if len(r.Features) == 0 {
+		if r.Bounds == (Bounds{}) || r.Bounds.Contains(pt) {
+			return r.DisplayName, true
+		}
+		return "", false
+	}
+	for _, f := range r.Features {
+		if f.Contains(pt) {
+			return f.Name, true
+		}
+	}
+	return "", false
+}
+
+// ServiceHoursHint is shown when no transit routes are found.
+func (r *Region) ServiceHoursHint() string {
+	if r.ServiceHours != "" {
+		return r.ServiceHours
+	}
+	return "transit may not be running at this time"
+}
@@ -0,0 +1,264 @@
+package score
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"seattle-commute-cli/transit"
+)
+
+type Planner interface {
+	GetRoutesAt(origin, destination string, departAt time.Time) ([]transit.Route, error)
+}
+
+type Walker interface {
+	GetWalkingDistance(origin, destination string) (time.Duration, string, error)
+}
+
+type Target struct {
+	Name  string
+	Query string
+}
+
+// When is a moment to evaluate. ToTarget is false for the trip back.
+type When struct {
+	Label    string
+	At       time.Time
+	ToTarget bool
+}
+
+// Sample is the best trip for one target at one time.
+type Sample struct {
+	Target            string
+	When              string
+	Duration          time.Duration
+	Transfers         int
+	Walk              time.Duration
+	DeparturesPerHour float64
+	Walkable          bool
+	Err               error
+}
+
+// Evaluate plans a trip between candidate and every target at every time.
+// Targets within maxWalk on foot are scored as a walk. Requests run
+// concurrently; samples come back in target, then time order.
+func Evaluate(planner Planner, walker Walker, candidate string, targets []Target, times []When, maxWalk time.Duration) []Sample {
+	samples := make([]Sample, len(targets)*len(times))
+
+	var wg sync.WaitGroup
+	for ti, target := range targets {
+		wg.Add(1)
+		go func(ti int, target Target) {
+			defer wg.Done()
+
+			walkTime, _, err := walker.GetWalkingDistance(candidate, target.Query)
+			walkable := err == nil && walkTime <= maxWalk
+
+			for wi, when := range times {
+				i := ti*len(times) + wi
+				if walkable {
+					samples[i] = Sample{
+						Target: target.Name, When: when.Label,
+						Duration: walkTime, Walk: walkTime, Walkable: true,
+					}
+					continue
+				}
+				samples[i] = sampleTransit(planner, candidate, target, when)
+			}
+		}(ti, target)
+	}
+	wg.Wait()
+
+	return samples
+}
+
+func sampleTransit(planner Planner, candidate string, target Target, when When) Sample {
+	sample := Sample{Target: target.Name, When: when.Label}
+
+	origin, destination := candidate, target.Query
+	if !when.ToTarget {
+		origin, destination = target.Query, candidate
+	}
+
+	routes, err := planner.GetRoutesAt(origin, destination, when.At)
+	if err != nil {
+		sample.Err = err
+		return sample
+	}
+	if len(routes) == 0 {
+		sample.Err = fmt.Errorf("no routes found")
+		return sample
+	}
+
+	best := routes[0]
+	for _, r := range routes[1:] {
+		if r.Duration < best.Duration {
+			best = r
+		}
+	}
+
+	sample.Duration = best.Duration
+	sample.Transfers = best.Transfers()
+	sample.Walk = best.WalkingTime()
+	sample.DeparturesPerHour = departuresPerHour(routes, when.At)
+	return sample
+}
+
+// departuresPerHour counts distinct departures in the hour after at. Google
+// only returns a handful of alternatives, so this undercounts very frequent
+// service, which is fine for comparing candidates.
+func departuresPerHour(routes []transit.Route, at time.Time) float64 {
+	seen := map[string]bool{}
+	for _, r := range routes {
+		if r.DepartureTime.Before(at) || r.DepartureTime.After(at.Add(time.Hour)) {
+			continue
+		}
+		seen[r.DepartureTime.Format("15:04")+strings.Join(r.Lines(), ">")] = true
+	}
+	return float64(len(seen))
+}
+
+// Weights of each component in the final score. They're normalized, so only
+// their ratios matter.
+type Weights struct {
+	Median    float64
+	Worst     float64
+	Transfers float64
+	Walk      float64
+	Frequency float64
+}
+
+var DefaultWeights = Weights{
+	Median:    0.35,
+	Worst:     0.2,
+	Transfers: 0.15,
+	Walk:      0.1,
+	Frequency: 0.2,
+}
+
+// WeightsFromMap overrides DefaultWeights with any of "median", "worst",
+// "transfers", "walk" and "frequency". 0 turns a component off.
+func WeightsFromMap(m map[string]float64) (Weights, error) {
+	w := DefaultWeights
+	for name, v := range m {
+		switch strings.ToLower(name) {
+		case "median":
+			w.Median = v
+		case "worst":
+			w.Worst = v
+		case "transfers":
+			w.Transfers = v
+		case "walk":
+			w.Walk = v
+		case "frequency":
+			w.Frequency = v
+		default:
+			return w, fmt.Errorf("unknown score weight %q (use median, worst, transfers, walk or frequency)", name)
+		}
+		if v < 0 {
+			return w, fmt.Errorf("score weight %q can't be negative (use 0 to ignore it)", name)
+		}
+	}
+	if w.Median+w.Worst+w.Transfers+w.Walk+w.Frequency == 0 {
+		return w, fmt.Errorf("at least one score weight must be above 0")
+	}
+	return w, nil
+}
+
+// Component is one part of the score, rated 0-100.
+type Component struct {
+	Name   string
+	Value  string
+	Rating float64
+	Weight float64
+}
+
+type Result struct {
+	Score      float64
+	Components []Component
+	Median     time.Duration
+	Worst      time.Duration
+	Failed     int
+}
+
+// Summarize rolls samples up into a 0-100 score. Failed samples count as
+// the worst case for travel time so unreachable targets aren't rewarded.
+func Summarize(samples []Sample, weights Weights) Result {
+	var durations []time.Duration
+	var transfers, walk, freq float64
+	var ok, transitSamples int
+	result := Result{}
+
+	for _, s := range samples {
+		if s.Err != nil {
+			result.Failed++
+			durations = append(durations, worstTravel)
+			continue
+		}
+		ok++
+		durations = append(durations, s.Duration)
+		transfers += float64(s.Transfers)
+		walk += s.Walk.Minutes()
+		if !s.Walkable {
+			freq += s.DeparturesPerHour
+			transitSamples++
+		}
+	}
+
+	if ok == 0 {
+		return result
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	result.Median = durations[len(durations)/2]
+	if len(durations)%2 == 0 {
+		result.Median = (durations[len(durations)/2-1] + durations[len(durations)/2]) / 2
+	}
+	result.Worst = durations[len(durations)-1]
+
+	avgTransfers := transfers / float64(ok)
+	avgWalk := walk / float64(ok)
+	avgFreq := 6.0 // walking is always "available"
+	if transitSamples > 0 {
+		avgFreq = freq / float64(transitSamples)
+	}
+
+	result.Components = []Component{
+		{"Median travel", formatMinutes(result.Median.Minutes()), linear(result.Median.Minutes(), 15, 90), weights.Median},
+		{"Worst travel", formatMinutes(result.Worst.Minutes()), linear(result.Worst.Minutes(), 20, 120), weights.Worst},
+		{"Transfers", fmt.Sprintf("%.1f avg", avgTransfers), linear(avgTransfers, 0, 3), weights.Transfers},
+		{"Walking", formatMinutes(avgWalk) + " avg", linear(avgWalk, 5, 25), weights.Walk},
+		{"Frequency", fmt.Sprintf("%.1f/hr", avgFreq), linear(-avgFreq, -6, -1), weights.Frequency},
+	}
+
+	var total, weightSum float64
+	for _, c := range result.Components {
+		total += c.Rating * c.Weight
+		weightSum += c.Weight
+	}
+	if weightSum > 0 {
+		result.Score = total / weightSum
+	}
+	return result
+}
+
+const worstTravel = 120 * time.Minute
+
+// linear rates v as 100 at or below best and 0 at or above worst.
+func linear(v, best, worst float64) float64 {
+	if v <= best {
+		return 100
+	}
+	if v >= worst {
+		return 0
+	}
+	return math.Round(100 * (worst - v) / (worst - best))
+}
+
+func formatMinutes(m float64) string {
+	return fmt.Sprintf("%.0f min", m)
+}
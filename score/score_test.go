@@ -0,0 +1,49 @@
+package score
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSummarizeCountsFailuresAsWorstCase(t *testing.T) {
+	samples := []Sample{{Duration: 20 * time.Minute, DeparturesPerHour: 6}}
+	for i := 0; i < 9; i++ {
+		samples = append(samples, Sample{Err: fmt.Errorf("no route")})
+	}
+
+	result := Summarize(samples, DefaultWeights)
+	if result.Failed != 9 {
+		t.Errorf("Failed = %d, want 9", result.Failed)
+	}
+	if result.Median != worstTravel || result.Worst != worstTravel {
+		t.Errorf("median %s, worst %s; want both %s", result.Median, result.Worst, worstTravel)
+	}
+
+	fine := Summarize(samples[:1], DefaultWeights)
+	if result.Score >= fine.Score {
+		t.Errorf("score with 9 failures %.0f, want below %.0f", result.Score, fine.Score)
+	}
+}
+
+func TestWeightsFromMap(t *testing.T) {
+	cases := []struct {
+		weights map[string]float64
+		wantErr bool
+	}{
+		{map[string]float64{"median": 0.5, "worst": 0, "walk": 0}, false},
+		{map[string]float64{"Frequency": 1}, false},
+		{map[string]float64{"commute": 1}, true},
+		{map[string]float64{"walk": -1}, true},
+		{map[string]float64{"median": 0, "worst": 0, "transfers": 0, "walk": 0, "frequency": 0}, true},
+	}
+	for _, c := range cases {
+		w, err := WeightsFromMap(c.weights)
+		if (err != nil) != c.wantErr {
+			t.Errorf("WeightsFromMap(%v) error = %v, want error %v", c.weights, err, c.wantErr)
+		}
+		if err == nil && c.weights["worst"] == 0 && c.weights["median"] == 0.5 && (w.Worst != 0 || w.Median != 0.5) {
+			t.Errorf("WeightsFromMap(%v) = %+v", c.weights, w)
+		}
+	}
+}
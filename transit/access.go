@@ -0,0 +1,89 @@
+package transit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Accessibility rules out routes someone can't take. The Directions API has
+// no wheelchair option (unlike the JavaScript API's transit options), so
+// this is all enforced on the routes that come back.
+type Accessibility struct {
+	Profile string
+	// MaxStepWalk caps each walking step; zero means no cap.
+	MaxStepWalk time.Duration
+	// StepFree avoids stops whose elevators are out, as reported by
+	// Outage.
+	StepFree bool
+	NoBike   bool
+	// Outage reports whether stop has no step-free access at t.
+	Outage func(stop string, t time.Time) bool
+}
+
+// AccessProfiles are the built-in accessibility profiles.
+var AccessProfiles = map[string]Accessibility{
+	"wheelchair":      {Profile: "wheelchair", MaxStepWalk: 10 * time.Minute, StepFree: true, NoBike: true},
+	"no-stairs":       {Profile: "no-stairs", MaxStepWalk: 10 * time.Minute, StepFree: true},
+	"limited-walking": {Profile: "limited-walking", MaxStepWalk: 5 * time.Minute},
+}
+
+// AccessProfile returns a built-in profile by name.
+func AccessProfile(name string) (Accessibility, error) {
+	a, ok := AccessProfiles[strings.ToLower(name)]
+	if !ok {
+		names := make([]string, 0, len(AccessProfiles))
+		for n := range AccessProfiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return Accessibility{}, fmt.Errorf("unknown accessibility profile %q (use %s)", name, strings.Join(names, ", "))
+	}
+	return a, nil
+}
+
+// Problems lists why r doesn't suit the profile; none means it does.
+func (a Accessibility) Problems(r Route) []string {
+	var problems []string
+	for _, step := range r.Steps {
+		switch step.Mode {
+		case "WALKING":
+			if a.MaxStepWalk > 0 && step.Duration > a.MaxStepWalk {
+				problems = append(problems, fmt.Sprintf("%.0fm walk", step.Duration.Minutes()))
+			}
+		case "BICYCLING":
+			if a.NoBike {
+				problems = append(problems, "bike leg")
+			}
+		case "TRANSIT":
+			if !a.StepFree || a.Outage == nil {
+				continue
+			}
+			if step.DepartureStop != "" && a.Outage(step.DepartureStop, step.DepartTime) {
+				problems = append(problems, "elevator out at "+step.DepartureStop)
+			}
+			if step.ArrivalStop != "" && a.Outage(step.ArrivalStop, step.ArrivalTime) {
+				problems = append(problems, "elevator out at "+step.ArrivalStop)
+			}
+		}
+	}
+	return problems
+}
+
+// Filter keeps the routes without problems and tallies the problems of the
+// rest.
+func (a Accessibility) Filter(routes []Route) (ok []Route, problems map[string]int) {
+	problems = make(map[string]int)
+	for _, r := range routes {
+		p := a.Problems(r)
+		if len(p) == 0 {
+			ok = append(ok, r)
+			continue
+		}
+		for _, reason := range p {
+			problems[reason]++
+		}
+	}
+	return ok, problems
+}
@@ -0,0 +1,71 @@
+package transit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Signature identifies the choice a route represents: the vehicles it
+// rides, where it boards each one and when. Routes with the same signature
+// are the same trip however Google summarized them.
+func (r Route) Signature() string {
+	var parts []string
+	for _, step := range r.Steps {
+		if step.Mode == "WALKING" {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s|%s|%s|%s", step.Mode, step.LineInfo, step.DepartureStop, step.DepartTime.Format("15:04")))
+	}
+	if len(parts) == 0 {
+		return "WALKING|" + r.DepartureTime.Format("15:04")
+	}
+	return strings.Join(parts, ">")
+}
+
+// dominates reports whether a is at least as good as b on every count -
+// leaving no earlier, arriving no later, no more transfers, no more
+// walking and no fewer rides on preferred lines - and better on one. A
+// route the preferences would filter out never dominates one they keep.
+func (p Preferences) dominates(a, b Route) bool {
+	if !p.Allows(a) && p.Allows(b) {
+		return false
+	}
+	if a.DepartureTime.Before(b.DepartureTime) || a.ArrivalTime.After(b.ArrivalTime) ||
+		a.Transfers() > b.Transfers() || a.WalkingTime() > b.WalkingTime() ||
+		p.preferredRides(a) < p.preferredRides(b) {
+		return false
+	}
+	return a.DepartureTime.After(b.DepartureTime) || a.ArrivalTime.Before(b.ArrivalTime) ||
+		a.Transfers() < b.Transfers() || a.WalkingTime() < b.WalkingTime() ||
+		p.preferredRides(a) > p.preferredRides(b)
+}
+
+// Distinct keeps one route per signature (the first) and drops routes
+// another route dominates, so what's left is a set of real choices. Order
+// is preserved.
+func (p Preferences) Distinct(routes []Route) []Route {
+	seen := make(map[string]bool)
+	var unique []Route
+	for _, r := range routes {
+		sig := r.Signature()
+		if !seen[sig] {
+			seen[sig] = true
+			unique = append(unique, r)
+		}
+	}
+
+	var distinct []Route
+	for i, r := range unique {
+		dominated := false
+		for j, other := range unique {
+			if i != j && p.dominates(other, r) {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			distinct = append(distinct, r)
+		}
+	}
+	return distinct
+}
@@ -0,0 +1,118 @@
+package transit
+
+import (
+	"testing"
+	"time"
+)
+
+var base = time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+
+// trip builds a route that walks for walk minutes and then rides each line
+// in turn, leaving at depart and arriving at arrive (minutes after base).
+func trip(summary string, depart, arrive, walk int, lines ...string) Route {
+	r := Route{
+		Summary:       summary,
+		DepartureTime: base.Add(time.Duration(depart) * time.Minute),
+		ArrivalTime:   base.Add(time.Duration(arrive) * time.Minute),
+	}
+	r.Duration = r.ArrivalTime.Sub(r.DepartureTime)
+	if walk > 0 {
+		r.Steps = append(r.Steps, Step{Mode: "WALKING", Duration: time.Duration(walk) * time.Minute})
+	}
+	for i, line := range lines {
+		r.Steps = append(r.Steps, Step{
+			Mode:          "TRANSIT",
+			LineInfo:      line,
+			Vehicle:       "BUS",
+			DepartureStop: line + " stop",
+			DepartTime:    r.DepartureTime.Add(time.Duration(walk+10*i) * time.Minute),
+		})
+	}
+	return r
+}
+
+func TestDistinct(t *testing.T) {
+	cases := []struct {
+		name   string
+		prefs  Preferences
+		routes []Route
+		want   []string
+	}{
+		{
+			name: "same signature merged",
+			routes: []Route{
+				trip("first", 0, 30, 5, "Bus 40"),
+				trip("", 0, 30, 5, "Bus 40"),
+			},
+			want: []string{"first"},
+		},
+		{
+			name: "dominated route dropped",
+			routes: []Route{
+				trip("slow", 0, 40, 5, "Bus 40"),
+				trip("fast", 0, 30, 5, "Bus 62"),
+			},
+			want: []string{"fast"},
+		},
+		{
+			name: "more walking is dominated",
+			routes: []Route{
+				trip("short walk", 0, 30, 3, "Bus 40"),
+				trip("long walk", 0, 30, 9, "Bus 62"),
+			},
+			want: []string{"short walk"},
+		},
+		{
+			name: "trade-offs kept",
+			routes: []Route{
+				trip("direct", 0, 40, 5, "Bus 40"),
+				trip("transfer", 0, 30, 5, "Bus 62", "Bus 8"),
+			},
+			want: []string{"direct", "transfer"},
+		},
+		{
+			name: "equal routes on different lines kept",
+			routes: []Route{
+				trip("40", 0, 30, 5, "Bus 40"),
+				trip("62", 0, 30, 5, "Bus 62"),
+			},
+			want: []string{"40", "62"},
+		},
+		{
+			name:  "avoided line never dominates",
+			prefs: Preferences{Lines: []LineRule{{Line: "62", Avoid: true}}},
+			routes: []Route{
+				trip("allowed", 0, 40, 5, "Bus 40"),
+				trip("avoided", 0, 30, 5, "Bus 62"),
+			},
+			want: []string{"allowed", "avoided"},
+		},
+		{
+			name:  "preferred line survives a faster route",
+			prefs: Preferences{Lines: []LineRule{{Line: "40"}}},
+			routes: []Route{
+				trip("preferred", 0, 40, 5, "Bus 40"),
+				trip("faster", 0, 30, 5, "Bus 62"),
+			},
+			want: []string{"preferred", "faster"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := c.prefs.Distinct(c.routes)
+			var names []string
+			for _, r := range got {
+				names = append(names, r.Summary)
+			}
+			if len(names) != len(c.want) {
+				t.Fatalf("got %q, want %q", names, c.want)
+			}
+			for i := range names {
+				if names[i] != c.want[i] {
+					t.Fatalf("got %q, want %q", names, c.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package transit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"googlemaps.github.io/maps"
+)
+
+// Preferences steer which routes we ask Google for and how the results are
+// ranked. Google only treats them as hints, so Apply enforces them again on
+// the routes that come back.
+type Preferences struct {
+	// Modes restricts the vehicles ridden: "bus", "rail", "tram" and/or
+	// "ferry". As with Google's transit_mode, "rail" includes trams and light
+	// rail.
+	Modes          []string
+	FewerTransfers bool
+	LessWalking    bool
+	Lines          []LineRule
+}
+
+// LineRule avoids or prefers a line. Line is matched with Step.OnLine, so
+// "545" matches "Bus 545" and "1 Line" matches "Light rail 1 Line".
+type LineRule struct {
+	Line  string
+	Avoid bool
+	// Active reports whether the rule applies when boarding at t; nil means
+	// always.
+	Active func(t time.Time) bool
+}
+
+func (lr LineRule) applies(step Step) bool {
+	return step.OnLine(lr.Line) && (lr.Active == nil || lr.Active(step.DepartTime))
+}
+
+// OnLine reports whether the step rides line, given as the full LineInfo
+// or its trailing words.
+func (s Step) OnLine(line string) bool {
+	info := strings.ToLower(strings.TrimSpace(s.LineInfo))
+	line = strings.ToLower(strings.TrimSpace(line))
+	return info == line || strings.HasSuffix(info, " "+line)
+}
+
+// Penalties used when ranking routes against the preferences.
+const (
+	TransferPenalty = 10 * time.Minute
+	// WalkingPenalty is applied per minute walked, on top of the walk itself.
+	WalkingPenalty = 1.0
+	// PreferredLineBonus is taken off per ride on a preferred line: a trip
+	// that stays on a preferred line can be this much slower and still win.
+	PreferredLineBonus = 5 * time.Minute
+)
+
+var transitModes = []string{"bus", "rail", "tram", "ferry"}
+
+// ParseModes validates and normalizes a list of transit modes.
+func ParseModes(modes []string) ([]string, error) {
+	var parsed []string
+	for _, m := range modes {
+		m = strings.ToLower(strings.TrimSpace(m))
+		if m == "" {
+			continue
+		}
+		known := false
+		for _, t := range transitModes {
+			if m == t {
+				known = true
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown transit mode %q (want %s)", m, strings.Join(transitModes, ", "))
+		}
+		parsed = append(parsed, m)
+	}
+	return parsed, nil
+}
+
+func (p Preferences) IsZero() bool {
+	return len(p.Modes) == 0 && !p.FewerTransfers && !p.LessWalking && len(p.Lines) == 0
+}
+
+// apply sets the request parameters Google understands. Ferries have no
+// transit_mode, so a mode list that includes them is only enforced
+// afterwards.
+func (p Preferences) apply(req *maps.DirectionsRequest) {
+	if p.FewerTransfers {
+		req.TransitRoutingPreference = maps.TransitRoutingPreferenceFewerTransfers
+	} else if p.LessWalking {
+		req.TransitRoutingPreference = maps.TransitRoutingPreferenceLessWalking
+	}
+
+	var modes []maps.TransitMode
+	for _, m := range p.Modes {
+		switch m {
+		case "bus":
+			modes = append(modes, maps.TransitModeBus)
+		case "rail":
+			modes = append(modes, maps.TransitModeRail)
+		case "tram":
+			modes = append(modes, maps.TransitModeTram)
+		default:
+			return
+		}
+	}
+	req.TransitMode = modes
+}
+
+// VehicleMode is the kind of vehicle the step rides: "bus", "rail",
+// "tram", "ferry" or "other".
+func (s Step) VehicleMode() string {
+	return vehicleMode(s.Vehicle)
+}
+
+// vehicleMode buckets Google's vehicle types into our transit modes.
+func vehicleMode(vehicleType string) string {
+	switch vehicleType {
+	case "BUS", "INTERCITY_BUS", "TROLLEYBUS", "SHARE_TAXI":
+		return "bus"
+	case "TRAM", "MONORAIL":
+		return "tram"
+	case "RAIL", "METRO_RAIL", "SUBWAY", "HEAVY_RAIL", "COMMUTER_TRAIN", "HIGH_SPEED_TRAIN", "LONG_DISTANCE_TRAIN":
+		return "rail"
+	case "FERRY":
+		return "ferry"
+	}
+	return "other"
+}
+
+// Allows reports whether every vehicle ridden on r is one of the preferred
+// modes and none of the rides is on an avoided line.
+func (p Preferences) Allows(r Route) bool {
+	for _, step := range r.TransitSteps() {
+		for _, lr := range p.Lines {
+			if lr.Avoid && lr.applies(step) {
+				return false
+			}
+		}
+		if len(p.Modes) == 0 {
+			continue
+		}
+		mode := vehicleMode(step.Vehicle)
+		ok := false
+		for _, m := range p.Modes {
+			if m == mode || (m == "rail" && mode == "tram") {
+				ok = true
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// preferredRides counts the rides on r that are on a preferred line.
+func (p Preferences) preferredRides(r Route) int {
+	n := 0
+	for _, step := range r.TransitSteps() {
+		for _, lr := range p.Lines {
+			if !lr.Avoid && lr.applies(step) {
+				n++
+				break
+			}
+		}
+	}
+	return n
+}
+
+// Cost is the arrival time penalized for transfers and walking and
+// credited for rides on preferred lines, used to rank routes. Without
+// preferences it is just the arrival time.
+func (p Preferences) Cost(r Route) time.Time {
+	cost := r.ArrivalTime.Add(-time.Duration(p.preferredRides(r)) * PreferredLineBonus)
+	if p.FewerTransfers {
+		cost = cost.Add(time.Duration(r.Transfers()) * TransferPenalty)
+	}
+	if p.LessWalking {
+		cost = cost.Add(time.Duration(float64(r.WalkingTime()) * WalkingPenalty))
+	}
+	return cost
+}
+
+// Apply drops routes that ride a vehicle outside the preferred modes or an
+// avoided line and, if any preference is set, ranks the rest by Cost. If
+// nothing fits, the routes are kept anyway, since an off-preference route
+// beats none; honored is false when that happens.
+func (p Preferences) Apply(routes []Route) (ranked []Route, honored bool) {
+	if p.IsZero() {
+		return routes, true
+	}
+
+	for _, r := range routes {
+		if p.Allows(r) {
+			ranked = append(ranked, r)
+		}
+	}
+	honored = len(ranked) > 0
+	if !honored {
+		ranked = append([]Route(nil), routes...)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return p.Cost(ranked[i]).Before(p.Cost(ranked[j]))
+	})
+	return ranked, honored
+}
@@ -0,0 +1,174 @@
+package transit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Strategy is how routes are ordered.
+type Strategy string
+
+const (
+	ByDeparture Strategy = "departure"
+	ByArrival   Strategy = "arrival"
+	ByDuration  Strategy = "duration"
+	ByTransfers Strategy = "transfers"
+	// ByScore weighs arrival, duration, transfers, walking, waiting and
+	// reliability together.
+	ByScore Strategy = "score"
+)
+
+var strategies = []Strategy{ByDeparture, ByArrival, ByDuration, ByTransfers, ByScore}
+
+func ParseStrategy(s string) (Strategy, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, st := range strategies {
+		if string(st) == s {
+			return st, nil
+		}
+	}
+	names := make([]string, len(strategies))
+	for i, st := range strategies {
+		names[i] = string(st)
+	}
+	return "", fmt.Errorf("unknown sort %q (use %s)", s, strings.Join(names, ", "))
+}
+
+// Weights price each part of a route in minutes of later arrival, for the
+// score strategy.
+type Weights struct {
+	// Duration is per minute of travel, so a later, shorter trip can beat
+	// an earlier, longer one.
+	Duration float64
+	// Transfers is per vehicle change.
+	Transfers float64
+	// Walking and Wait are per minute walked and per minute spent waiting
+	// at transfers.
+	Walking float64
+	Wait    float64
+	// Reliability is the cost of a trip that certainly goes wrong; it is
+	// scaled by the chance the route doesn't run as planned.
+	Reliability float64
+}
+
+var DefaultWeights = Weights{
+	Duration:    0.2,
+	Transfers:   6,
+	Walking:     0.5,
+	Wait:        0.5,
+	Reliability: 20,
+}
+
+// WeightsFromMap overrides DefaultWeights with any of "duration",
+// "transfers", "walking", "wait" and "reliability".
+func WeightsFromMap(m map[string]float64) (Weights, error) {
+	w := DefaultWeights
+	for name, v := range m {
+		switch strings.ToLower(name) {
+		case "duration":
+			w.Duration = v
+		case "transfers":
+			w.Transfers = v
+		case "walking":
+			w.Walking = v
+		case "wait":
+			w.Wait = v
+		case "reliability":
+			w.Reliability = v
+		default:
+			return w, fmt.Errorf("unknown ranking weight %q (use duration, transfers, walking, wait or reliability)", name)
+		}
+	}
+	return w, nil
+}
+
+// Ranker orders routes by a strategy.
+type Ranker struct {
+	Strategy Strategy
+	Weights  Weights
+	// Prefs adjusts the score for preferred lines, transfers and walking.
+	Prefs Preferences
+	// Reliability estimates the chance a route runs as planned (0-1); nil
+	// uses EstimateReliability.
+	Reliability func(Route) float64
+}
+
+// Score is the route's effective arrival time under the score strategy:
+// its arrival plus the weighted penalties. Earlier is better.
+func (rk Ranker) Score(r Route) time.Time {
+	reliability := EstimateReliability
+	if rk.Reliability != nil {
+		reliability = rk.Reliability
+	}
+
+	minutes := rk.Weights.Duration*r.Duration.Minutes() +
+		rk.Weights.Transfers*float64(r.Transfers()) +
+		rk.Weights.Walking*r.WalkingTime().Minutes() +
+		rk.Weights.Wait*r.TransferWait().Minutes() +
+		rk.Weights.Reliability*(1-reliability(r))
+
+	return rk.Prefs.Cost(r).Add(time.Duration(minutes * float64(time.Minute)))
+}
+
+// Sort orders routes in place. Ties keep their existing order, so earlier
+// departures stay first.
+func (rk Ranker) Sort(routes []Route) {
+	var less func(a, b Route) bool
+	switch rk.Strategy {
+	case ByArrival:
+		less = func(a, b Route) bool { return a.ArrivalTime.Before(b.ArrivalTime) }
+	case ByDuration:
+		less = func(a, b Route) bool { return a.Duration < b.Duration }
+	case ByTransfers:
+		less = func(a, b Route) bool {
+			if a.Transfers() != b.Transfers() {
+				return a.Transfers() < b.Transfers()
+			}
+			return a.ArrivalTime.Before(b.ArrivalTime)
+		}
+	case ByScore:
+		less = func(a, b Route) bool { return rk.Score(a).Before(rk.Score(b)) }
+	default:
+		less = func(a, b Route) bool { return a.DepartureTime.Before(b.DepartureTime) }
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		return less(routes[i], routes[j])
+	})
+}
+
+// TransferWait is the time spent standing around between rides: the gap
+// between getting off one vehicle and boarding the next, less any walking
+// in between.
+func (r Route) TransferWait() time.Duration {
+	var total time.Duration
+	for _, c := range (TransferPolicy{}).Connections(r) {
+		total += max(c.Slack, 0)
+	}
+	return total
+}
+
+// onTime is a rough chance each kind of vehicle runs to schedule.
+var onTime = map[string]float64{
+	"rail":  0.97,
+	"tram":  0.95,
+	"ferry": 0.95,
+	"bus":   0.85,
+	"other": 0.9,
+}
+
+// EstimateReliability guesses the chance a route runs as planned from the
+// vehicles it rides, until we have measured data.
+func EstimateReliability(r Route) float64 {
+	p := 1.0
+	for _, step := range r.TransitSteps() {
+		p *= onTime[vehicleMode(step.Vehicle)]
+	}
+	return p
+}
+
+func sortByDeparture(routes []Route) {
+	Ranker{Strategy: ByDeparture}.Sort(routes)
+}
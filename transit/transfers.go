@@ -0,0 +1,120 @@
+package transit
+
+import (
+	"math"
+	"time"
+)
+
+// DefaultTransferMinimums is the slack a connection needs, keyed by
+// "from-to" vehicle mode. Getting off a train and finding a bus stop takes
+// longer than crossing a platform.
+var DefaultTransferMinimums = map[string]time.Duration{
+	"default":    3 * time.Minute,
+	"rail-bus":   5 * time.Minute,
+	"tram-bus":   4 * time.Minute,
+	"bus-ferry":  10 * time.Minute,
+	"rail-ferry": 10 * time.Minute,
+}
+
+// TransferPolicy judges the connections on a route.
+type TransferPolicy struct {
+	// Minimums override DefaultTransferMinimums per "from-to" mode pair. A
+	// "default" here replaces all the built-in values, pairs included.
+	Minimums map[string]time.Duration
+	// MeanDelays is the typical lateness of each mode ("bus", "rail", ...).
+	// When known, connections get a chance of being made.
+	MeanDelays map[string]time.Duration
+}
+
+// Connection is the change between two consecutive rides.
+type Connection struct {
+	From, To Step
+	// Walk is the walking between the two rides; Slack is what's left of
+	// the gap after it.
+	Walk    time.Duration
+	Slack   time.Duration
+	Minimum time.Duration
+	// Chance of making the connection, or -1 without delay data.
+	Chance float64
+}
+
+func (c Connection) Tight() bool {
+	return c.Slack < c.Minimum
+}
+
+// Stop is where the change happens.
+func (c Connection) Stop() string {
+	if c.From.ArrivalStop != "" {
+		return c.From.ArrivalStop
+	}
+	return c.To.DepartureStop
+}
+
+// minimum looks for the pair, then "default", in Minimums before falling
+// back to DefaultTransferMinimums the same way.
+func (tp TransferPolicy) minimum(from, to Step) time.Duration {
+	key := vehicleMode(from.Vehicle) + "-" + vehicleMode(to.Vehicle)
+	for _, m := range []map[string]time.Duration{tp.Minimums, DefaultTransferMinimums} {
+		if d, ok := m[key]; ok {
+			return d
+		}
+		if d, ok := m["default"]; ok {
+			return d
+		}
+	}
+	return 0
+}
+
+// Connections lists the changes between rides on r.
+func (tp TransferPolicy) Connections(r Route) []Connection {
+	var conns []Connection
+	var prev *Step
+	var walk time.Duration
+	for i := range r.Steps {
+		step := r.Steps[i]
+		switch step.Mode {
+		case "WALKING":
+			walk += step.Duration
+		case "TRANSIT":
+			if prev != nil && !prev.ArrivalTime.IsZero() && !step.DepartTime.IsZero() {
+				c := Connection{
+					From:    *prev,
+					To:      step,
+					Walk:    walk,
+					Slack:   step.DepartTime.Sub(prev.ArrivalTime) - walk,
+					Minimum: tp.minimum(*prev, step),
+					Chance:  -1,
+				}
+				if mean, ok := tp.MeanDelays[vehicleMode(prev.Vehicle)]; ok && mean > 0 {
+					// Lateness modeled as exponential with the given mean.
+					c.Chance = 0
+					if c.Slack > 0 {
+						c.Chance = 1 - math.Exp(-float64(c.Slack)/float64(mean))
+					}
+				}
+				conns = append(conns, c)
+			}
+			prev, walk = &r.Steps[i], 0
+		}
+	}
+	return conns
+}
+
+// tightChance is the assumed chance of making a tight connection when we
+// have no delay data to estimate it.
+const tightChance = 0.75
+
+// Reliability is EstimateReliability further discounted by the chance of
+// making each connection, for use in Ranker.
+func (tp TransferPolicy) Reliability(r Route) float64 {
+	p := EstimateReliability(r)
+	for _, c := range tp.Connections(r) {
+		switch {
+		case c.Chance >= 0:
+			p *= c.Chance
+		case c.Tight():
+			p *= tightChance
+		}
+	}
+	return p
+}
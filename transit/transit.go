@@ -3,7 +3,6 @@ package transit
 import (
 	"context"
 	"fmt"
-	"sort"
 	"strings"
 	"time"
 
@@ -11,12 +10,26 @@ import (
 )
 
 type Route struct {
-	Summary      string
-	Duration     time.Duration
+	Summary       string
+	Duration      time.Duration
 	DepartureTime time.Time
 	ArrivalTime   time.Time
-	Steps        []Step
-	Distance     string
+	Steps         []Step
+	Distance      string
+	// Fare is Google's total fare for the route, when it has one.
+	Fare         float64
+	FareCurrency string
+	// Alerts are service alerts affecting the route, see package alerts.
+	Alerts []Alert
+}
+
+// Alert is a service alert attached to a route.
+type Alert struct {
+	// Effect is the GTFS-realtime effect, e.g. DETOUR or NO_SERVICE.
+	Effect      string
+	Header      string
+	Description string
+	URL         string
 }
 
 type Step struct {
@@ -24,16 +37,62 @@ type Step struct {
 	Duration     time.Duration
 	Mode         string
 	LineInfo     string
-	DepartTime   time.Time
-	ArrivalTime  time.Time
+	// Vehicle is Google's vehicle type for transit steps, e.g. "BUS".
+	Vehicle       string
+	Agency        string
+	DepartureStop string
+	ArrivalStop   string
+	DepartTime    time.Time
+	ArrivalTime   time.Time
+}
+
+// TransitSteps returns the steps that ride a transit vehicle.
+func (r Route) TransitSteps() []Step {
+	var steps []Step
+	for _, step := range r.Steps {
+		if step.Mode == "TRANSIT" {
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+// Transfers is the number of vehicle changes.
+func (r Route) Transfers() int {
+	return max(len(r.TransitSteps())-1, 0)
+}
+
+// WalkingTime is the total time spent walking.
+func (r Route) WalkingTime() time.Duration {
+	var total time.Duration
+	for _, step := range r.Steps {
+		if step.Mode == "WALKING" {
+			total += step.Duration
+		}
+	}
+	return total
+}
+
+// Lines lists the transit lines in riding order.
+func (r Route) Lines() []string {
+	var lines []string
+	for _, step := range r.TransitSteps() {
+		if step.LineInfo != "" {
+			lines = append(lines, step.LineInfo)
+		}
+	}
+	return lines
 }
 
 type TransitService struct {
 	client *maps.Client
+	prefs  Preferences
 }
 
-func NewTransitService(apiKey string) (*TransitService, error) {
-	client, err := maps.NewClient(maps.WithAPIKey(apiKey))
+// NewTransitService creates a service for apiKey. Extra client options,
+// e.g. maps.WithRateLimit, are passed through to the Maps client.
+func NewTransitService(apiKey string, options ...maps.ClientOption) (*TransitService, error) {
+	client, err := maps.NewClient(append([]maps.ClientOption{maps.WithAPIKey(apiKey)}, options...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Google Maps client: %v", err)
 	}
@@ -41,18 +100,35 @@ func NewTransitService(apiKey string) (*TransitService, error) {
 	return &TransitService{client: client}, nil
 }
 
-func (ts *TransitService) GetRoutes(origin, destination string) ([]Route, error) {
-	ctx := context.Background()
-	now := time.Now()
+// SetPreferences passes route preferences on to every Directions request.
+func (ts *TransitService) SetPreferences(p Preferences) {
+	ts.prefs = p
+}
 
+// newRequest builds a transit Directions request with the preferences
+// applied.
+func (ts *TransitService) newRequest(origin, destination, departure string) *maps.DirectionsRequest {
 	req := &maps.DirectionsRequest{
 		Origin:        origin,
 		Destination:   destination,
 		Mode:          maps.TravelModeTransit,
-		DepartureTime: fmt.Sprintf("%d", now.Unix()),
+		DepartureTime: departure,
 		Alternatives:  true,
 		Units:         maps.UnitsImperial,
 	}
+	ts.prefs.apply(req)
+	return req
+}
+
+func (ts *TransitService) GetRoutes(origin, destination string) ([]Route, error) {
+	return ts.GetRoutesAt(origin, destination, time.Now())
+}
+
+// GetRoutesAt returns transit routes departing at or after departAt.
+func (ts *TransitService) GetRoutesAt(origin, destination string, departAt time.Time) ([]Route, error) {
+	ctx := context.Background()
+
+	req := ts.newRequest(origin, destination, fmt.Sprintf("%d", departAt.Unix()))
 
 	resp, _, err := ts.client.Directions(ctx, req)
 	if err != nil {
@@ -69,44 +145,10 @@ func (ts *TransitService) GetRoutes(origin, destination string) ([]Route, error)
 			continue
 		}
 
-		leg := route.Legs[0]
-		r := Route{
-			Summary:      route.Summary,
-			Duration:     leg.Duration,
-			DepartureTime: leg.DepartureTime,
-			ArrivalTime:   leg.ArrivalTime,
-			Distance:     leg.Distance.HumanReadable,
-		}
-
-		for _, step := range leg.Steps {
-			s := Step{
-				Instructions: cleanHTML(step.HTMLInstructions),
-				Duration:     step.Duration,
-				Mode:         string(step.TravelMode),
-			}
-
-			if step.TransitDetails != nil {
-				s.DepartTime = step.TransitDetails.DepartureTime
-				s.ArrivalTime = step.TransitDetails.ArrivalTime
-
-				if step.TransitDetails.Line.ShortName != "" {
-					s.LineInfo = fmt.Sprintf("%s %s",
-						step.TransitDetails.Line.Vehicle.Name,
-						step.TransitDetails.Line.ShortName)
-				} else {
-					s.LineInfo = step.TransitDetails.Line.Name
-				}
-			}
-
-			r.Steps = append(r.Steps, s)
-		}
-
-		routes = append(routes, r)
+		routes = append(routes, convertRoute(route))
 	}
 
-	sort.Slice(routes, func(i, j int) bool {
-		return routes[i].DepartureTime.Before(routes[j].DepartureTime)
-	})
+	sortByDeparture(routes)
 
 	return routes, nil
 }
@@ -114,14 +156,7 @@ func (ts *TransitService) GetRoutes(origin, destination string) ([]Route, error)
 func (ts *TransitService) GetNextRoutes(origin, destination string, hours int) ([]Route, error) {
 	ctx := context.Background()
 
-	req := &maps.DirectionsRequest{
-		Origin:        origin,
-		Destination:   destination,
-		Mode:          maps.TravelModeTransit,
-		DepartureTime: "now",
-		Alternatives:  true,
-		Units:         maps.UnitsImperial,
-	}
+	req := ts.newRequest(origin, destination, "now")
 
 	resp, _, err := ts.client.Directions(ctx, req)
 	if err != nil {
@@ -138,53 +173,18 @@ func (ts *TransitService) GetNextRoutes(origin, destination string, hours int) (
 			continue
 		}
 
-		leg := route.Legs[0]
-
-		if leg.DepartureTime.Before(time.Now().Add(-5 * time.Minute)) {
+		if route.Legs[0].DepartureTime.Before(time.Now().Add(-5 * time.Minute)) {
 			continue
 		}
 
-		r := Route{
-			Summary:      route.Summary,
-			Duration:     leg.Duration,
-			DepartureTime: leg.DepartureTime,
-			ArrivalTime:   leg.ArrivalTime,
-			Distance:     leg.Distance.HumanReadable,
-		}
-
-		for _, step := range leg.Steps {
-			s := Step{
-				Instructions: cleanHTML(step.HTMLInstructions),
-				Duration:     step.Duration,
-				Mode:         string(step.TravelMode),
-			}
-
-			if step.TransitDetails != nil {
-				s.DepartTime = step.TransitDetails.DepartureTime
-				s.ArrivalTime = step.TransitDetails.ArrivalTime
-
-				if step.TransitDetails.Line.ShortName != "" {
-					s.LineInfo = fmt.Sprintf("%s %s",
-						step.TransitDetails.Line.Vehicle.Name,
-						step.TransitDetails.Line.ShortName)
-				} else {
-					s.LineInfo = step.TransitDetails.Line.Name
-				}
-			}
-
-			r.Steps = append(r.Steps, s)
-		}
-
-		routes = append(routes, r)
+		routes = append(routes, convertRoute(route))
 	}
 
 	if len(routes) == 0 {
 		return ts.getFallbackRoutes(origin, destination, hours)
 	}
 
-	sort.Slice(routes, func(i, j int) bool {
-		return routes[i].DepartureTime.Before(routes[j].DepartureTime)
-	})
+	sortByDeparture(routes)
 
 	return routes, nil
 }
@@ -196,13 +196,8 @@ func (ts *TransitService) getFallbackRoutes(origin, destination string, hours in
 		departTime := time.Now().Add(time.Duration(i*20) * time.Minute)
 
 		ctx := context.Background()
-		req := &maps.DirectionsRequest{
-			Origin:        origin,
-			Destination:   destination,
-			Mode:          maps.TravelModeTransit,
-			DepartureTime: fmt.Sprintf("%d", departTime.Unix()),
-			Units:         maps.UnitsImperial,
-		}
+		req := ts.newRequest(origin, destination, fmt.Sprintf("%d", departTime.Unix()))
+		req.Alternatives = false
 
 		resp, _, err := ts.client.Directions(ctx, req)
 		if err != nil {
@@ -213,50 +208,78 @@ func (ts *TransitService) getFallbackRoutes(origin, destination string, hours in
 			continue
 		}
 
-		route := resp[0]
-		leg := route.Legs[0]
+		allRoutes = append(allRoutes, convertRoute(resp[0]))
+	}
 
-		r := Route{
-			Summary:      route.Summary,
-			Duration:     leg.Duration,
-			DepartureTime: leg.DepartureTime,
-			ArrivalTime:   leg.ArrivalTime,
-			Distance:     leg.Distance.HumanReadable,
-		}
+	uniqueRoutes := removeDuplicateRoutes(allRoutes)
 
-		for _, step := range leg.Steps {
-			s := Step{
-				Instructions: cleanHTML(step.HTMLInstructions),
-				Duration:     step.Duration,
-				Mode:         string(step.TravelMode),
-			}
+	sortByDeparture(uniqueRoutes)
 
-			if step.TransitDetails != nil {
-				s.DepartTime = step.TransitDetails.DepartureTime
-				s.ArrivalTime = step.TransitDetails.ArrivalTime
-
-				if step.TransitDetails.Line.ShortName != "" {
-					s.LineInfo = fmt.Sprintf("%s %s",
-						step.TransitDetails.Line.Vehicle.Name,
-						step.TransitDetails.Line.ShortName)
-				} else {
-					s.LineInfo = step.TransitDetails.Line.Name
-				}
-			}
+	return uniqueRoutes, nil
+}
+
+// removeDuplicateRoutes drops routes with the same Signature, which the
+// fallback's overlapping requests can return more than once.
+func removeDuplicateRoutes(routes []Route) []Route {
+	seen := make(map[string]bool)
+	var unique []Route
 
-			r.Steps = append(r.Steps, s)
+	for _, route := range routes {
+		sig := route.Signature()
+		if !seen[sig] {
+			seen[sig] = true
+			unique = append(unique, route)
 		}
+	}
+
+	return unique
+}
 
-		allRoutes = append(allRoutes, r)
+// convertRoute flattens the first leg of a Directions route into a Route.
+func convertRoute(route maps.Route) Route {
+	leg := route.Legs[0]
+	r := Route{
+		Summary:       route.Summary,
+		Duration:      leg.Duration,
+		DepartureTime: leg.DepartureTime,
+		ArrivalTime:   leg.ArrivalTime,
+		Distance:      leg.Distance.HumanReadable,
+	}
+	if route.Fare != nil {
+		r.Fare = route.Fare.Value
+		r.FareCurrency = route.Fare.Currency
 	}
 
-	uniqueRoutes := removeDuplicateRoutes(allRoutes)
+	for _, step := range leg.Steps {
+		s := Step{
+			Instructions: cleanHTML(step.HTMLInstructions),
+			Duration:     step.Duration,
+			Mode:         string(step.TravelMode),
+		}
 
-	sort.Slice(uniqueRoutes, func(i, j int) bool {
-		return uniqueRoutes[i].DepartureTime.Before(uniqueRoutes[j].DepartureTime)
-	})
+		if step.TransitDetails != nil {
+			s.DepartTime = step.TransitDetails.DepartureTime
+			s.ArrivalTime = step.TransitDetails.ArrivalTime
+			s.Vehicle = step.TransitDetails.Line.Vehicle.Type
+			s.DepartureStop = step.TransitDetails.DepartureStop.Name
+			s.ArrivalStop = step.TransitDetails.ArrivalStop.Name
+			if agencies := step.TransitDetails.Line.Agencies; len(agencies) > 0 && agencies[0] != nil {
+				s.Agency = agencies[0].Name
+			}
 
-	return uniqueRoutes, nil
+			if step.TransitDetails.Line.ShortName != "" {
+				s.LineInfo = fmt.Sprintf("%s %s",
+					step.TransitDetails.Line.Vehicle.Name,
+					step.TransitDetails.Line.ShortName)
+			} else {
+				s.LineInfo = step.TransitDetails.Line.Name
+			}
+		}
+
+		r.Steps = append(r.Steps, s)
+	}
+
+	return r
 }
 
 func cleanHTML(html string) string {
@@ -267,22 +290,3 @@ func cleanHTML(html string) string {
 	html = strings.ReplaceAll(html, "<div style=\"font-size:0.9em\">", " - ")
 	return html
 }
-
-func removeDuplicateRoutes(routes []Route) []Route {
-	seen := make(map[string]bool)
-	var unique []Route
-
-	for _, route := range routes {
-		key := fmt.Sprintf("%s_%s_%s",
-			route.Summary,
-			route.DepartureTime.Format("15:04"),
-			route.ArrivalTime.Format("15:04"))
-
-		if !seen[key] {
-			seen[key] = true
-			unique = append(unique, route)
-		}
-	}
-
-	return unique
-}
\ No newline at end of file
@@ -0,0 +1,126 @@
+package trips
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"seattle-commute-cli/transit"
+)
+
+// MinSamples is how many trips a reliability factor needs before we trust
+// it.
+const MinSamples = 3
+
+// Period buckets a time of day: "morning" (6-10), "midday" (10-15),
+// "evening" (15-19) or "night".
+func Period(t time.Time) string {
+	switch h := t.Hour(); {
+	case h >= 6 && h < 10:
+		return "morning"
+	case h >= 10 && h < 15:
+		return "midday"
+	case h >= 15 && h < 19:
+		return "evening"
+	}
+	return "night"
+}
+
+// Reliability turns completed trips into a personal factor to scale
+// planned durations by.
+type Reliability struct {
+	trips []Trip
+	loc   *time.Location
+}
+
+func NewReliability(trips []Trip, loc *time.Location) *Reliability {
+	return &Reliability{trips: trips, loc: loc}
+}
+
+// Factor is the median actual/planned ratio of past trips to the same
+// destination most like r: on the same lines at the same time of day, then
+// on any lines at that time of day. match says which ("on these lines" or
+// "to <to>"); it returns 1 and 0 samples without enough data. Trips that
+// ended before their planned departure are ignored.
+func (rl *Reliability) Factor(r transit.Route, to string) (factor float64, samples int, match string) {
+	lines := strings.Join(r.Lines(), ">")
+	period := Period(r.DepartureTime.In(rl.loc))
+
+	levels := []struct {
+		match string
+		keep  func(Trip) bool
+	}{
+		{"on these lines", func(t Trip) bool { return strings.Join(t.Lines, ">") == lines }},
+		{"to " + to, func(t Trip) bool { return true }},
+	}
+	for _, level := range levels {
+		var ratios []float64
+		for _, t := range rl.trips {
+			if t.Actual() > 0 && t.To == to && Period(t.Depart.In(rl.loc)) == period && level.keep(t) {
+				ratios = append(ratios, t.Ratio())
+			}
+		}
+		if len(ratios) >= MinSamples {
+			return median(ratios), len(ratios), level.match
+		}
+	}
+	return 1, 0, ""
+}
+
+// Group is the planned vs actual record for one set of lines at one time of
+// day.
+type Group struct {
+	Lines   string
+	Period  string
+	Trips   int
+	Planned time.Duration
+	Actual  time.Duration
+}
+
+// Groups summarizes the trips by lines and time of day, most trips first.
+// Like Factor, it skips trips that ended before their planned departure.
+func (rl *Reliability) Groups() []Group {
+	groups := make(map[[2]string]*Group)
+	for _, t := range rl.trips {
+		if t.Actual() <= 0 {
+			continue
+		}
+		lines := strings.Join(t.Lines, " → ")
+		if lines == "" {
+			lines = "(walk)"
+		}
+		key := [2]string{lines, Period(t.Depart.In(rl.loc))}
+		g, ok := groups[key]
+		if !ok {
+			g = &Group{Lines: key[0], Period: key[1]}
+			groups[key] = g
+		}
+		g.Trips++
+		g.Planned += t.Duration
+		g.Actual += t.Actual()
+	}
+
+	list := make([]Group, 0, len(groups))
+	for _, g := range groups {
+		g.Planned /= time.Duration(g.Trips)
+		g.Actual /= time.Duration(g.Trips)
+		list = append(list, *g)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Trips != list[j].Trips {
+			return list[i].Trips > list[j].Trips
+		}
+		return list[i].Lines < list[j].Lines
+	})
+	return list
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
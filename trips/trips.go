@@ -0,0 +1,193 @@
+package trips
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"seattle-commute-cli/transit"
+)
+
+// Planned is a route as it was shown, so 'commute start' can pick it.
+type Planned struct {
+	From     string        `json:"from"`
+	To       string        `json:"to"`
+	Kind     string        `json:"kind"`
+	Lines    []string      `json:"lines,omitempty"`
+	Depart   time.Time     `json:"depart"`
+	Arrive   time.Time     `json:"arrive"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Trip is a planned route paired with when you actually set off and got
+// there.
+type Trip struct {
+	Planned
+	StartedAt time.Time `json:"started_at"`
+	ArrivedAt time.Time `json:"arrived_at,omitzero"`
+}
+
+// Actual is how long the trip took, counted from the planned departure if
+// you set off early and waited.
+func (t Trip) Actual() time.Duration {
+	from := t.StartedAt
+	if t.Depart.After(from) {
+		from = t.Depart
+	}
+	return t.ArrivedAt.Sub(from)
+}
+
+// Ratio is actual over planned duration; above 1 means slower than planned.
+func (t Trip) Ratio() float64 {
+	if t.Duration <= 0 {
+		return 1
+	}
+	return float64(t.Actual()) / float64(t.Duration)
+}
+
+func dir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".seattle-commute")
+}
+
+func lastRoutesPath() string { return filepath.Join(dir(), "last-routes.json") }
+func activePath() string     { return filepath.Join(dir(), "trip.json") }
+
+// Path is the log of completed trips.
+func Path() string { return filepath.Join(dir(), "trips.jsonl") }
+
+// SaveShown remembers the routes just shown, in order.
+func SaveShown(from, to, kind string, routes []transit.Route) error {
+	shown := make([]Planned, len(routes))
+	for i, r := range routes {
+		shown[i] = Planned{
+			From:     from,
+			To:       to,
+			Kind:     kind,
+			Lines:    r.Lines(),
+			Depart:   r.DepartureTime,
+			Arrive:   r.ArrivalTime,
+			Duration: r.Duration,
+		}
+	}
+	return writeJSON(lastRoutesPath(), shown)
+}
+
+// StaleAfter is how long after its planned departure a shown route can
+// still be started. Later than that it's a different trip, and its
+// departure would put it in the wrong time-of-day bucket.
+const StaleAfter = 30 * time.Minute
+
+// Shown returns the routes last shown.
+func Shown() ([]Planned, error) {
+	var shown []Planned
+	if err := readJSON(lastRoutesPath(), &shown); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return shown, nil
+}
+
+// Start makes p the active trip, replacing any other.
+func Start(p Planned, at time.Time) (*Trip, error) {
+	t := &Trip{Planned: p, StartedAt: at}
+	if err := writeJSON(activePath(), t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Active returns the trip in progress, or nil.
+func Active() (*Trip, error) {
+	var t Trip
+	if err := readJSON(activePath(), &t); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Arrive completes the active trip and adds it to the log.
+func Arrive(at time.Time) (*Trip, error) {
+	t, err := Active()
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, fmt.Errorf("no trip in progress (run 'commute start' when you set off)")
+	}
+	t.ArrivedAt = at
+
+	f, err := os.OpenFile(Path(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return nil, err
+	}
+
+	return t, os.Remove(activePath())
+}
+
+// Cancel drops the active trip without recording it.
+func Cancel() error {
+	err := os.Remove(activePath())
+	if os.IsNotExist(err) {
+		return fmt.Errorf("no trip in progress")
+	}
+	return err
+}
+
+// Load reads the completed trips.
+func Load() ([]Trip, error) {
+	f, err := os.Open(Path())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trips: %v", err)
+	}
+	defer f.Close()
+
+	var trips []Trip
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var t Trip
+		if err := json.Unmarshal(scanner.Bytes(), &t); err != nil {
+			continue
+		}
+		trips = append(trips, t)
+	}
+	return trips, scanner.Err()
+}
+
+func writeJSON(path string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -3,78 +3,88 @@ package validation
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"googlemaps.github.io/maps"
+	"seattle-commute-cli/config"
+	"seattle-commute-cli/geo"
+	"seattle-commute-cli/region"
 )
 
 type AddressValidator struct {
 	client *maps.Client
+	region *region.Region
 }
 
-func NewAddressValidator(apiKey string) (*AddressValidator, error) {
+// NewAddressValidator creates a validator that checks addresses against the
+// region's service area. A nil region disables the check.
+func NewAddressValidator(apiKey string, r *region.Region) (*AddressValidator, error) {
 	client, err := maps.NewClient(maps.WithAPIKey(apiKey))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Google Maps client: %v", err)
 	}
-	return &AddressValidator{client: client}, nil
+	return &AddressValidator{client: client, region: r}, nil
 }
 
-func (av *AddressValidator) ValidateSeattleAddress(address string) (string, error) {
+func (av *AddressValidator) geocode(address string) (maps.GeocodingResult, error) {
 	ctx := context.Background()
 
 	req := &maps.GeocodingRequest{
 		Address: address,
 	}
+	if av.region != nil && av.region.Bounds != (region.Bounds{}) {
+		req.Bounds = &maps.LatLngBounds{
+			NorthEast: maps.LatLng{Lat: av.region.Bounds.North, Lng: av.region.Bounds.East},
+			SouthWest: maps.LatLng{Lat: av.region.Bounds.South, Lng: av.region.Bounds.West},
+		}
+	}
 
 	resp, err := av.client.Geocode(ctx, req)
 	if err != nil {
-		return "", fmt.Errorf("failed to validate address: %v", err)
+		return maps.GeocodingResult{}, fmt.Errorf("failed to validate address: %v", err)
 	}
 
 	if len(resp) == 0 {
-		return "", fmt.Errorf("address not found. Try being more specific (e.g., '123 Main St, Seattle, WA')")
+		return maps.GeocodingResult{}, fmt.Errorf("address not found. Try being more specific (e.g., '123 Main St, Seattle, WA')")
 	}
 
-	result := resp[0]
-
-	isInSeattleArea := false
-	var city, state, formattedAddress string
+	return resp[0], nil
+}
 
-	for _, component := range result.AddressComponents {
-		for _, typ := range component.Types {
-			switch typ {
-			case "locality":
-				city = component.LongName
-			case "administrative_area_level_1":
-				state = component.ShortName
-			}
-		}
+func placeFromResult(address string, result maps.GeocodingResult) *config.Place {
+	return &config.Place{
+		Address:          address,
+		FormattedAddress: result.FormattedAddress,
+		PlaceID:          result.PlaceID,
+		Lat:              result.Geometry.Location.Lat,
+		Lng:              result.Geometry.Location.Lng,
 	}
+}
 
-	formattedAddress = result.FormattedAddress
-
-	seattleAreaCities := []string{
-		"Seattle", "Bellevue", "Redmond", "Kirkland", "Bothell", "Lynnwood",
-		"Everett", "Renton", "Kent", "Federal Way", "Tacoma", "Burien",
-		"Shoreline", "Edmonds", "Mukilteo", "Mill Creek", "Woodinville",
+// Geocode resolves an address without checking the service area.
+func (av *AddressValidator) Geocode(address string) (*config.Place, error) {
+	result, err := av.geocode(address)
+	if err != nil {
+		return nil, err
 	}
+	return placeFromResult(address, result), nil
+}
 
-	for _, seattleCity := range seattleAreaCities {
-		if strings.Contains(strings.ToLower(city), strings.ToLower(seattleCity)) ||
-		   strings.Contains(strings.ToLower(formattedAddress), strings.ToLower(seattleCity)) {
-			isInSeattleArea = true
-			break
-		}
+// ValidateAddress geocodes address and checks that it falls inside the
+// validator's service area.
+func (av *AddressValidator) ValidateAddress(address string) (*config.Place, error) {
+	result, err := av.geocode(address)
+	if err != nil {
+		return nil, err
 	}
 
-	if state == "WA" && strings.Contains(strings.ToLower(formattedAddress), "king county") {
-		isInSeattleArea = true
+	place := placeFromResult(address, result)
+	if av.region == nil {
+		return place, nil
 	}
 
-	if !isInSeattleArea {
-		return "", fmt.Errorf("⚠️  '%s' appears to be outside the Seattle area (%s, %s). This tool works best with Seattle-area addresses", address, city, state)
+	if _, ok := av.region.Locate(geo.Point{Lat: place.Lat, Lng: place.Lng}); !ok {
+		return nil, fmt.Errorf("⚠️  '%s' (%s) appears to be outside the %s service area. This tool works best with addresses inside it", address, place.Label(), av.region.DisplayName)
 	}
 
-	return formattedAddress, nil
-}
\ No newline at end of file
+	return place, nil
+}